@@ -0,0 +1,118 @@
+package accesslog
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/redact"
+)
+
+type Options struct {
+	SampleRate    float64
+	SlowThreshold time.Duration
+}
+
+// Logger writes the access log.
+type Logger struct {
+	logger         *zap.Logger
+	redactor       *redact.Redactor
+	trustForwarded bool
+	opts           atomic.Pointer[Options]
+}
+
+func New(cfg *config.AccessLog, trustForwarded bool, redactor *redact.Redactor, logger *zap.Logger) *Logger {
+	l := &Logger{
+		logger:         logger.Named("access"),
+		redactor:       redactor,
+		trustForwarded: trustForwarded,
+	}
+
+	l.SetOptions(cfg)
+
+	return l
+}
+
+func (l *Logger) SetOptions(cfg *config.AccessLog) {
+	l.opts.Store(&Options{
+		SampleRate:    cfg.SampleRate,
+		SlowThreshold: cfg.SlowThreshold,
+	})
+}
+
+// outcome classifies a finished call; failed calls are never sampled away.
+type outcome struct {
+	failed      bool
+	serverError bool
+}
+
+func (l *Logger) write(msg string, o outcome, duration time.Duration, e *Entry, fields []zap.Field) {
+	opts := l.opts.Load()
+
+	slow := opts.SlowThreshold > 0 && duration >= opts.SlowThreshold
+
+	if !o.failed && !slow && (opts.SampleRate <= 0 || opts.SampleRate < 1 && rand.Float64() >= opts.SampleRate) {
+		return
+	}
+
+	level := zapcore.InfoLevel
+	switch {
+	case o.serverError:
+		level = zapcore.ErrorLevel
+	case o.failed || slow:
+		level = zapcore.WarnLevel
+	}
+
+	ce := l.logger.Check(level, msg)
+	if ce == nil {
+		return
+	}
+
+	fields = append(fields, zap.Duration("duration", duration))
+
+	if slow {
+		fields = append(fields, zap.Bool("slow", true))
+	}
+
+	e.mx.Lock()
+	fields = appendString(fields, "route", e.route)
+	fields = appendString(fields, "rpc", e.rpc)
+	fields = appendString(fields, "user_id", e.userID)
+	fields = appendString(fields, "request_id", e.requestID)
+	fields = appendString(fields, "trace_id", e.traceID)
+	fields = appendString(fields, "upstream", e.upstream)
+	fields = appendString(fields, "upstream_addr", e.upstreamAddr)
+	if e.hasCode {
+		fields = append(fields, zap.String("grpc_code", e.code.String()))
+	}
+	e.mx.Unlock()
+
+	ce.Write(fields...)
+}
+
+func (l *Logger) debug() bool {
+	return l.logger.Core().Enabled(zapcore.DebugLevel)
+}
+
+func appendString(fields []zap.Field, key, value string) []zap.Field {
+	if value == "" {
+		return fields
+	}
+
+	return append(fields, zap.String(key, value))
+}
+
+// serverCode reports the codes that do not point at the caller.
+func serverCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+package accesslog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/siderolabs/grpc-proxy/proxy"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/redact"
+)
+
+const secret = "s3cr3t-value"
+
+var calls = []struct {
+	fullMethod string
+	query      string
+	msg        proto.Message
+}{
+	{
+		fullMethod: usersv1.UsersAuthService_Register_FullMethodName,
+		query:      "password=" + secret,
+		msg:        &usersv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: secret},
+	},
+	{
+		fullMethod: usersv1.UsersAuthService_Login_FullMethodName,
+		query:      "password=" + secret,
+		msg:        &usersv1.LoginRequest{Identifier: &usersv1.LoginRequest_Email{Email: "alice@example.com"}, Password: secret},
+	},
+	{
+		fullMethod: usersv1.UsersProfileService_ChangePassword_FullMethodName,
+		query:      "new_password=" + secret,
+		msg:        &usersv1.ChangePasswordRequest{UserId: "1", Password: secret},
+	},
+	{
+		fullMethod: usersv1.UsersAuthService_OAuthLogin_FullMethodName,
+		query:      "provider=github&code=" + secret,
+		msg:        &usersv1.OAuthLoginRequest{Provider: "github", Token: secret, Code: secret},
+	},
+}
+
+func newTestLogger() (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := New(&config.AccessLog{SampleRate: 1}, false, redact.New(&config.Redaction{}), zap.New(core))
+
+	return l, logs
+}
+
+// requireRedacted fails when a secret shows up in any logged message or field.
+func requireRedacted(t *testing.T, logs *observer.ObservedLogs, wantEntries int) {
+	t.Helper()
+
+	entries := logs.AllUntimed()
+	if len(entries) != wantEntries {
+		t.Fatalf("logged %d entries, want %d", len(entries), wantEntries)
+	}
+
+	for _, e := range entries {
+		if out := fmt.Sprint(e.Message, e.ContextMap()); strings.Contains(out, secret) {
+			t.Fatalf("secret leaked: %s", out)
+		}
+	}
+}
+
+func TestHTTPRedaction(t *testing.T) {
+	for _, call := range calls {
+		t.Run(call.fullMethod, func(t *testing.T) {
+			l, logs := newTestLogger()
+
+			body, err := protojson.Marshal(call.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if entry, ok := FromContext(r.Context()); ok {
+					entry.SetRoute("", call.fullMethod)
+				}
+
+				_, _ = io.Copy(io.Discard, r.Body)
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/v1/users?"+call.query, strings.NewReader(string(body)))
+			r.Header.Set("Authorization", "Bearer "+secret)
+			r.Header.Set("X-Api-Key", secret)
+			r.Header.Set("Cookie", "session="+secret)
+
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			requireRedacted(t, logs, 1)
+		})
+	}
+}
+
+func TestPayloadDump(t *testing.T) {
+	for _, call := range calls {
+		t.Run(call.fullMethod, func(t *testing.T) {
+			l, logs := newTestLogger()
+			h := l.ClientHandler("users")
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: call.fullMethod})
+
+			data, err := proto.Marshal(call.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h.HandleRPC(ctx, &stats.OutPayload{Payload: call.msg})
+			h.HandleRPC(ctx, &stats.OutPayload{Payload: proxy.NewFrame(data)})
+
+			requireRedacted(t, logs, 2)
+
+			for _, e := range logs.AllUntimed() {
+				if msg := e.ContextMap()["message"]; !strings.Contains(fmt.Sprint(msg), redact.Placeholder) {
+					t.Fatalf("expected a redacted message, got %v", msg)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCMetadataRedaction(t *testing.T) {
+	l, logs := newTestLogger()
+	h := l.ServerHandler()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer "+secret,
+		"x-api-key", secret,
+	))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: usersv1.UsersAuthService_Login_FullMethodName})
+
+	h.HandleRPC(ctx, &stats.End{Error: errors.New("denied")})
+
+	requireRedacted(t, logs, 1)
+}
@@ -0,0 +1,100 @@
+package accesslog
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+type entryKey struct{}
+
+// Entry collects what the layers below the access log learn about a call.
+type Entry struct {
+	mx           sync.Mutex
+	route        string
+	rpc          string
+	userID       string
+	traceID      string
+	requestID    string
+	upstream     string
+	upstreamAddr string
+	code         codes.Code
+	hasCode      bool
+}
+
+func NewContext(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+func FromContext(ctx context.Context) (*Entry, bool) {
+	e, ok := ctx.Value(entryKey{}).(*Entry)
+	return e, ok
+}
+
+func (e *Entry) SetRoute(route, rpc string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.route = route
+	e.rpc = rpc
+}
+
+func (e *Entry) SetUserID(userID string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.userID = userID
+}
+
+func (e *Entry) SetTraceID(traceID string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.traceID == "" {
+		e.traceID = traceID
+	}
+}
+
+func (e *Entry) SetRequestID(requestID string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.requestID = requestID
+}
+
+// SetUpstream records the upstream of the last attempt.
+func (e *Entry) SetUpstream(upstream, addr string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.upstream = upstream
+	if addr != "" {
+		e.upstreamAddr = addr
+	}
+}
+
+// input is the request message of the matched rpc, if it is known.
+func (e *Entry) input() protoreflect.MessageDescriptor {
+	e.mx.Lock()
+	rpc := e.rpc
+	e.mx.Unlock()
+
+	md, err := registry.FindMethod(rpc)
+	if err != nil {
+		return nil
+	}
+
+	return md.Input()
+}
+
+func (e *Entry) SetCode(code codes.Code) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.code = code
+	e.hasCode = true
+}
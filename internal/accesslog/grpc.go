@@ -0,0 +1,175 @@
+package accesslog
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+type rpcKey struct{}
+
+type rpcInfo struct {
+	entry      *Entry
+	fullMethod string
+	bytesIn    atomic.Int64
+	bytesOut   atomic.Int64
+}
+
+var _ stats.Handler = (*serverHandler)(nil)
+
+type serverHandler struct {
+	l *Logger
+}
+
+// ServerHandler logs every call of a gRPC server.
+func (l *Logger) ServerHandler() stats.Handler {
+	return &serverHandler{l: l}
+}
+
+func (h *serverHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if _, ok := FromContext(ctx); ok {
+		return ctx
+	}
+
+	rpc := &rpcInfo{entry: &Entry{rpc: info.FullMethodName}, fullMethod: info.FullMethodName}
+
+	return context.WithValue(NewContext(ctx, rpc.entry), rpcKey{}, rpc)
+}
+
+func (h *serverHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	rpc, ok := ctx.Value(rpcKey{}).(*rpcInfo)
+	if !ok {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.InPayload:
+		rpc.bytesIn.Add(int64(s.WireLength))
+	case *stats.OutPayload:
+		rpc.bytesOut.Add(int64(s.WireLength))
+	case *stats.End:
+		code := status.Code(s.Error)
+		rpc.entry.SetCode(code)
+
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			rpc.entry.SetTraceID(sc.TraceID().String())
+		}
+
+		fields := []zap.Field{
+			zap.String("protocol", "grpc"),
+			zap.Int64("bytes_in", rpc.bytesIn.Load()),
+			zap.Int64("bytes_out", rpc.bytesOut.Load()),
+			zap.String("client_ip", clientip.FromContext(ctx, h.l.trustForwarded)),
+		}
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok && h.l.debug() {
+			fields = append(fields, zap.Any("metadata", h.l.redactor.Metadata(md)))
+		}
+
+		h.l.write("grpc call", outcome{failed: s.Error != nil, serverError: serverCode(code)},
+			s.EndTime.Sub(s.BeginTime), rpc.entry, fields)
+	}
+}
+
+func (h *serverHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *serverHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// StreamServerInterceptor runs behind auth, so the caller is verified.
+func (l *Logger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if entry, ok := FromContext(ss.Context()); ok {
+			if id := requestid.FromContext(ss.Context()); id != "" {
+				entry.SetRequestID(id)
+			}
+
+			if claims, ok := auth.ClaimsFromContext(ss.Context()); ok {
+				entry.SetUserID(claims.SubjectID())
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+var _ stats.Handler = (*clientHandler)(nil)
+
+type methodKey struct{}
+
+type clientHandler struct {
+	l        *Logger
+	upstream string
+}
+
+// ClientHandler records the upstream address and status of a call.
+func (l *Logger) ClientHandler(upstream string) stats.Handler {
+	return &clientHandler{l: l, upstream: upstream}
+}
+
+func (h *clientHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (h *clientHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	switch s := s.(type) {
+	case *stats.OutPayload:
+		h.dump(ctx, "request", s.Payload)
+	case *stats.InPayload:
+		h.dump(ctx, "response", s.Payload)
+	}
+
+	entry, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.OutHeader:
+		var addr string
+		if s.RemoteAddr != nil {
+			addr = s.RemoteAddr.String()
+		}
+
+		entry.SetUpstream(h.upstream, addr)
+	case *stats.End:
+		entry.SetCode(status.Code(s.Error))
+	}
+}
+
+func (h *clientHandler) dump(ctx context.Context, direction string, payload any) {
+	if !h.l.debug() {
+		return
+	}
+
+	fullMethod, _ := ctx.Value(methodKey{}).(string)
+
+	msg, ok := decodePayload(fullMethod, direction == "request", payload)
+	if !ok {
+		return
+	}
+
+	requestid.Logger(ctx, h.l.logger).Debug("grpc payload",
+		zap.String("rpc", fullMethod),
+		zap.String("upstream", h.upstream),
+		zap.String("direction", direction),
+		zap.String("message", h.l.redactor.JSONMessage(msg)),
+	)
+}
+
+func (h *clientHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *clientHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,88 @@
+package accesslog
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/middlewares"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+// Handler logs every request once the response is written.
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		entry := &Entry{requestID: requestid.FromContext(r.Context())}
+		r = r.WithContext(NewContext(r.Context(), entry))
+
+		body := middlewares.NewCountingReader(r.Body)
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+
+		rec := middlewares.NewStatusRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			entry.SetTraceID(sc.TraceID().String())
+		}
+
+		status := rec.Status()
+
+		fields := []zap.Field{
+			zap.String("protocol", "http"),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", status),
+			zap.Int64("bytes_in", body.Bytes()),
+			zap.Int("bytes_out", rec.Bytes()),
+			zap.String("client_ip", clientip.FromRequest(r, l.trustForwarded)),
+			zap.String("user_agent", r.UserAgent()),
+		}
+
+		if r.URL.RawQuery != "" {
+			fields = append(fields, zap.String("query", l.redactor.Query(r.URL.RawQuery, entry.input())))
+		}
+
+		if l.debug() {
+			fields = append(fields, zap.Any("headers", l.redactor.Header(r.Header)))
+		}
+
+		l.write("http request", outcome{failed: status >= http.StatusBadRequest, serverError: status >= http.StatusInternalServerError},
+			time.Since(start), entry, fields)
+	})
+}
+
+// Middleware fills the entry with the matched route and the caller.
+func (l *Logger) Middleware() runtime.Middleware {
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if entry, ok := FromContext(r.Context()); ok {
+				var route string
+				if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+					route = pattern.String()
+				}
+
+				entry.SetRoute(route, auth.MethodFromRequest(r))
+
+				if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+					entry.SetUserID(claims.SubjectID())
+				}
+
+				if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+					entry.SetTraceID(sc.TraceID().String())
+				}
+			}
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package accesslog
+
+import (
+	"github.com/siderolabs/grpc-proxy/proxy"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+var codec = proxy.Codec()
+
+// decodePayload returns the message of a payload.
+func decodePayload(fullMethod string, request bool, payload any) (proto.Message, bool) {
+	if msg, ok := payload.(proto.Message); ok {
+		return msg, true
+	}
+
+	md, err := registry.FindMethod(fullMethod)
+	if err != nil {
+		return nil, false
+	}
+
+	desc := md.Output()
+	if request {
+		desc = md.Input()
+	}
+
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := codec.Marshal(payload)
+	if err != nil {
+		return nil, false
+	}
+	defer data.Free()
+
+	msg := msgType.New().Interface()
+	if err = proto.Unmarshal(data.Materialize(), msg); err != nil {
+		return nil, false
+	}
+
+	return msg, true
+}
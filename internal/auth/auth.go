@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
+)
+
+type Authenticator struct {
+	verifier *Verifier
+	policy   atomic.Pointer[Policy]
+	logger   *zap.Logger
+}
+
+func NewAuthenticator(verifier *Verifier, policy *Policy, logger *zap.Logger) *Authenticator {
+	a := &Authenticator{
+		verifier: verifier,
+		logger:   logger,
+	}
+
+	a.policy.Store(policy)
+
+	return a
+}
+
+func (a *Authenticator) SetPolicy(policy *Policy) {
+	a.policy.Store(policy)
+}
+
+func (a *Authenticator) IsPublic(fullMethod string) bool {
+	return a.policy.Load().IsPublic(fullMethod)
+}
+
+func (a *Authenticator) Run(ctx context.Context) {
+	a.verifier.Run(ctx)
+}
+
+// Authenticate verifies the bearer token carried in header for fullMethod.
+func (a *Authenticator) Authenticate(ctx context.Context, fullMethod, header string) (*Claims, error) {
+	public := a.IsPublic(fullMethod)
+
+	token, ok := tokenFromHeader(header)
+	if !ok {
+		if public {
+			return nil, nil
+		}
+
+		return nil, apperrors.Unauthorized(AccessTokenNotProvidedError)
+	}
+
+	claims, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		if public {
+			return nil, nil
+		}
+
+		requestid.Logger(ctx, a.logger).Debug("request rejected", zap.String("method", fullMethod), zap.Error(err))
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	AuthorizationHeader = "authorization"
+	Bearer              = "Bearer "
+)
+
+const (
+	UserIDMetadataKey  = "x-user-id"
+	RoleMetadataKey    = "x-user-role"
+	TokenIDMetadataKey = "x-token-id"
+)
+
+var claimsMetadataKeys = []string{UserIDMetadataKey, RoleMetadataKey, TokenIDMetadataKey}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// SubjectID prefers the user_id claim over the registered subject.
+func (c *Claims) SubjectID() string {
+	if c.UserID != "" {
+		return c.UserID
+	}
+
+	return c.Subject
+}
+
+func (c *Claims) Metadata() metadata.MD {
+	md := metadata.Pairs(UserIDMetadataKey, c.SubjectID())
+
+	if c.Role != "" {
+		md.Set(RoleMetadataKey, c.Role)
+	}
+
+	if c.ID != "" {
+		md.Set(TokenIDMetadataKey, c.ID)
+	}
+
+	return md
+}
+
+type claimsKey struct{}
+
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// StripMetadata removes caller supplied claim keys.
+func StripMetadata(md metadata.MD) {
+	for _, key := range claimsMetadataKeys {
+		delete(md, key)
+	}
+}
+
+func isClaimsMetadataKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, k := range claimsMetadataKeys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tokenFromHeader(header string) (string, bool) {
+	if len(header) < len(Bearer) || !strings.EqualFold(header[:len(Bearer)], Bearer) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(Bearer):])
+
+	return token, token != ""
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+
+		var header string
+		if values := metadata.ValueFromIncomingContext(ctx, AuthorizationHeader); len(values) > 0 {
+			header = values[0]
+		}
+
+		claims, err := a.Authenticate(ctx, info.FullMethod, header)
+		if err != nil {
+			return err
+		}
+
+		if claims == nil {
+			return handler(srv, ss)
+		}
+
+		wrapped := grpcmiddleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ContextWithClaims(ctx, claims)
+
+		return handler(srv, wrapped)
+	}
+}
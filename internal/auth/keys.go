@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+)
+
+const maxJWKSSize = 1 << 20
+
+type Key struct {
+	ID        string
+	Algorithm string
+	Key       any
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	K   string `json:"k"`
+}
+
+// KeySource fetches a raw JWKS document.
+type KeySource interface {
+	Fetch(ctx context.Context) ([]byte, error)
+	String() string
+}
+
+type fileKeySource struct {
+	path string
+}
+
+func NewFileKeySource(path string) KeySource {
+	return &fileKeySource{path: path}
+}
+
+func (s *fileKeySource) Fetch(_ context.Context) ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+func (s *fileKeySource) String() string {
+	return "file:" + s.path
+}
+
+type httpKeySource struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPKeySource(url string) KeySource {
+	return &httpKeySource{
+		url:    url,
+		client: &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (s *httpKeySource) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks response status: %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+func (s *httpKeySource) String() string {
+	return s.url
+}
+
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("error decoding jwks: %w", err)
+	}
+
+	keys := make([]Key, 0, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.parse()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing jwk %q: %w", k.Kid, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (k *jwk) parse() (Key, error) {
+	key := Key{ID: k.Kid, Algorithm: k.Alg}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return key, err
+		}
+
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return key, err
+		}
+
+		key.Key = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		if key.Algorithm == "" {
+			key.Algorithm = "RS256"
+		}
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return key, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return key, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return key, errors.New("invalid ed25519 public key size")
+		}
+
+		key.Key = ed25519.PublicKey(x)
+		key.Algorithm = "EdDSA"
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil {
+			return key, err
+		}
+
+		key.Key = secret
+
+		if key.Algorithm == "" {
+			key.Algorithm = "HS256"
+		}
+	default:
+		return key, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+
+	return key, nil
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return base64.RawURLEncoding.DecodeString(s)
+}
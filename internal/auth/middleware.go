@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+func NewMiddleware(authenticator *Authenticator, errHandler runtime.ErrorHandlerFunc) runtime.Middleware {
+	marshaler := &runtime.JSONPb{}
+
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			ctx := r.Context()
+
+			claims, err := authenticator.Authenticate(ctx, MethodFromRequest(r), r.Header.Get(AuthorizationHeader))
+			if err != nil {
+				errHandler(ctx, nil, marshaler, w, r, err)
+				return
+			}
+
+			if claims != nil {
+				r = r.WithContext(ContextWithClaims(ctx, claims))
+			}
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
+
+// MethodFromRequest returns the gRPC method the runtime mux matched for r.
+func MethodFromRequest(r *http.Request) string {
+	if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+		if fullMethod, ok := registry.MethodForRoute(r.Method, pattern.String()); ok {
+			return fullMethod
+		}
+
+		return pattern.String()
+	}
+
+	return r.URL.Path
+}
+
+// Metadata is a runtime.WithMetadata annotator forwarding verified claims upstream.
+func Metadata(ctx context.Context, _ *http.Request) metadata.MD {
+	if claims, ok := ClaimsFromContext(ctx); ok {
+		return claims.Metadata()
+	}
+
+	return nil
+}
+
+// HeaderMatcher drops headers that would spoof claims metadata.
+func HeaderMatcher(key string) (string, bool) {
+	name, ok := runtime.DefaultHeaderMatcher(key)
+	if !ok || isClaimsMetadataKey(name) {
+		return "", false
+	}
+
+	return name, true
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+)
+
+var (
+	// builtinPublicMethods stay public whatever else is configured.
+	builtinPublicMethods = []string{
+		"/grpc.reflection.v1.ServerReflection/",
+		"/grpc.reflection.v1alpha.ServerReflection/",
+		"/grpc.health.v1.Health/",
+	}
+
+	defaultPublicMethods = []string{
+		usersv1.UsersAuthService_Register_FullMethodName,
+		usersv1.UsersAuthService_Login_FullMethodName,
+		usersv1.UsersAuthService_OAuthLogin_FullMethodName,
+		questionsv1.QuestionsClientService_GetCategories_FullMethodName,
+	}
+)
+
+// Policy decides which calls may pass without a token.
+type Policy struct {
+	methods  map[string]struct{}
+	prefixes []string
+}
+
+func NewPolicy(public []string) *Policy {
+	p := &Policy{
+		methods: make(map[string]struct{}),
+	}
+
+	if len(public) == 0 {
+		public = defaultPublicMethods
+	}
+
+	for _, entry := range slices.Concat(public, builtinPublicMethods) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(entry, "/") {
+			entry = "/" + entry
+		}
+
+		if strings.HasSuffix(entry, "/") {
+			p.prefixes = append(p.prefixes, entry)
+		} else {
+			p.methods[entry] = struct{}{}
+		}
+	}
+
+	return p
+}
+
+func (p *Policy) IsPublic(fullMethod string) bool {
+	if _, ok := p.methods[fullMethod]; ok {
+		return true
+	}
+
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
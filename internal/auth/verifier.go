@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
+)
+
+const (
+	AccessTokenNotProvidedError = "access token not provided"
+	AccessTokenInvalidError     = "access token invalid"
+	AccessTokenExpiredError     = "access token expired"
+)
+
+// minRefreshInterval keeps forged kid values from hammering the key endpoint.
+const minRefreshInterval = time.Second * 30
+
+type Verifier struct {
+	static          []Key
+	sources         []KeySource
+	keys            atomic.Pointer[[]Key]
+	parser          *jwt.Parser
+	refreshInterval time.Duration
+	lastRefresh     time.Time
+	refreshMx       sync.Mutex
+	logger          *zap.Logger
+}
+
+func NewVerifier(ctx context.Context, cfg *config.Auth, logger *zap.Logger) *Verifier {
+	v := &Verifier{
+		refreshInterval: cfg.RefreshInterval,
+		logger:          logger,
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+
+	if cfg.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(parserOpts...)
+
+	if cfg.Secret != "" {
+		v.static = append(v.static, Key{Algorithm: jwt.SigningMethodHS256.Alg(), Key: []byte(cfg.Secret)})
+	}
+
+	if cfg.JWKSFile != "" {
+		v.sources = append(v.sources, NewFileKeySource(cfg.JWKSFile))
+	}
+
+	if cfg.JWKSURL != "" {
+		v.sources = append(v.sources, NewHTTPKeySource(cfg.JWKSURL))
+	}
+
+	if len(v.static) == 0 && len(v.sources) == 0 {
+		logger.Warn("no jwt verification keys configured, every protected call will be rejected")
+	}
+
+	if err := v.Refresh(ctx); err != nil {
+		logger.Error("error loading jwks", zap.Error(err))
+	}
+
+	return v
+}
+
+func (v *Verifier) Run(ctx context.Context) {
+	if len(v.sources) == 0 || v.refreshInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(v.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := v.Refresh(ctx); err != nil {
+					v.logger.Warn("error refreshing jwks", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Refresh reloads every key source.
+func (v *Verifier) Refresh(ctx context.Context) error {
+	v.refreshMx.Lock()
+	defer v.refreshMx.Unlock()
+
+	return v.refresh(ctx)
+}
+
+func (v *Verifier) refresh(ctx context.Context) error {
+	keys := make([]Key, 0, len(v.static))
+	keys = append(keys, v.static...)
+
+	var errs error
+	var failed bool
+
+	for _, source := range v.sources {
+		data, err := source.Fetch(ctx)
+		if err == nil {
+			var parsed []Key
+			if parsed, err = ParseJWKS(data); err == nil {
+				keys = append(keys, parsed...)
+				continue
+			}
+		}
+
+		failed = true
+		errs = multierr.Append(errs, err)
+		v.logger.Warn("error loading keys", zap.String("source", source.String()), zap.Error(err))
+	}
+
+	if failed {
+		if prev := v.keys.Load(); prev != nil {
+			keys = mergeKeys(keys, *prev)
+		}
+	}
+
+	v.lastRefresh = time.Now()
+	v.keys.Store(&keys)
+
+	v.logger.Debug("jwt keys loaded", zap.Int("amount", len(keys)))
+
+	return errs
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if token == "" {
+		return nil, apperrors.Unauthorized(AccessTokenNotProvidedError)
+	}
+
+	claims := &Claims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return v.lookup(ctx, t)
+	})
+
+	switch {
+	case err == nil:
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, apperrors.Unauthorized(AccessTokenExpiredError)
+	default:
+		requestid.Logger(ctx, v.logger).Debug("token verification failed", zap.Error(err))
+		return nil, apperrors.Unauthorized(AccessTokenInvalidError)
+	}
+
+	if claims.SubjectID() == "" {
+		return nil, apperrors.Unauthorized(AccessTokenInvalidError)
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) lookup(ctx context.Context, t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	alg := t.Method.Alg()
+
+	set := v.match(kid, alg)
+	if len(set.Keys) == 0 && kid != "" && v.refreshUnknown(ctx) {
+		set = v.match(kid, alg)
+	}
+
+	if len(set.Keys) == 0 {
+		return nil, errors.New("no matching verification key")
+	}
+
+	return set, nil
+}
+
+func (v *Verifier) match(kid, alg string) jwt.VerificationKeySet {
+	var set jwt.VerificationKeySet
+
+	keys := v.keys.Load()
+	if keys == nil {
+		return set
+	}
+
+	for _, k := range *keys {
+		if k.Algorithm != "" && k.Algorithm != alg {
+			continue
+		}
+
+		if kid != "" && k.ID != "" && k.ID != kid {
+			continue
+		}
+
+		set.Keys = append(set.Keys, k.Key)
+	}
+
+	return set
+}
+
+func (v *Verifier) refreshUnknown(ctx context.Context) bool {
+	if len(v.sources) == 0 {
+		return false
+	}
+
+	v.refreshMx.Lock()
+	defer v.refreshMx.Unlock()
+
+	if time.Since(v.lastRefresh) < minRefreshInterval {
+		return false
+	}
+
+	_ = v.refresh(ctx)
+
+	return true
+}
+
+func mergeKeys(current, previous []Key) []Key {
+	seen := make(map[string]struct{}, len(current))
+	for _, k := range current {
+		if k.ID != "" {
+			seen[k.ID] = struct{}{}
+		}
+	}
+
+	for _, k := range previous {
+		if k.ID == "" {
+			continue
+		}
+
+		if _, ok := seen[k.ID]; !ok {
+			current = append(current, k)
+		}
+	}
+
+	return current
+}
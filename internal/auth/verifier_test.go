@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+const secret = "test-secret"
+
+type stubSource struct {
+	data    []byte
+	fetches int
+}
+
+func (s *stubSource) Fetch(_ context.Context) ([]byte, error) {
+	s.fetches++
+	return s.data, nil
+}
+
+func (s *stubSource) String() string {
+	return "stub"
+}
+
+type signingKey struct {
+	id      string
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+func newSigningKey(t *testing.T, id string) signingKey {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signingKey{id: id, private: private, public: public}
+}
+
+func encodeJWKS(t *testing.T, keys ...signingKey) []byte {
+	t.Helper()
+
+	set := jwks{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jwk{Kty: "OKP", Crv: "Ed25519", Kid: k.id, X: base64.RawURLEncoding.EncodeToString(k.public)})
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func newTestVerifier(t *testing.T, src *stubSource) *Verifier {
+	t.Helper()
+
+	v := NewVerifier(context.Background(), &config.Auth{
+		Secret:     secret,
+		Leeway:     30 * time.Second,
+		Algorithms: []string{"HS256", "RS256", "EdDSA"},
+	}, zap.NewNop())
+
+	v.sources = []KeySource{src}
+	if err := v.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims *Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func claimsFor(subject string, expiresIn time.Duration) *Claims {
+	now := time.Now()
+
+	return &Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+	}}
+}
+
+func TestVerify(t *testing.T) {
+	k1 := newSigningKey(t, "k1")
+	other := newSigningKey(t, "k1")
+	v := newTestVerifier(t, &stubSource{data: encodeJWKS(t, k1)})
+
+	withUserID := claimsFor("42", time.Hour)
+	withUserID.UserID = "7"
+
+	noExpiry := claimsFor("42", time.Hour)
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name    string
+		token   string
+		subject string
+		err     string
+	}{
+		{name: "empty", err: AccessTokenNotProvidedError},
+		{name: "hmac secret", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claimsFor("42", time.Hour)), subject: "42"},
+		{name: "jwks key", token: sign(t, jwt.SigningMethodEdDSA, k1.private, "k1", claimsFor("42", time.Hour)), subject: "42"},
+		{name: "user id claim", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", withUserID), subject: "7"},
+		{name: "algorithm not allowed", token: sign(t, jwt.SigningMethodHS384, []byte(secret), "", claimsFor("42", time.Hour)), err: AccessTokenInvalidError},
+		{name: "none algorithm", token: sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claimsFor("42", time.Hour)), err: AccessTokenInvalidError},
+		{name: "public key as hmac secret", token: sign(t, jwt.SigningMethodHS256, []byte(k1.public), "k1", claimsFor("42", time.Hour)), err: AccessTokenInvalidError},
+		{name: "wrong key for kid", token: sign(t, jwt.SigningMethodEdDSA, other.private, "k1", claimsFor("42", time.Hour)), err: AccessTokenInvalidError},
+		{name: "expired within leeway", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claimsFor("42", -10*time.Second)), subject: "42"},
+		{name: "expired beyond leeway", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claimsFor("42", -time.Minute)), err: AccessTokenExpiredError},
+		{name: "missing expiry", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", noExpiry), err: AccessTokenInvalidError},
+		{name: "missing subject", token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claimsFor("", time.Hour)), err: AccessTokenInvalidError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(context.Background(), tt.token)
+
+			if tt.err != "" {
+				requireUnauthenticated(t, err, tt.err)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if claims.SubjectID() != tt.subject {
+				t.Fatalf("subject = %q, want %q", claims.SubjectID(), tt.subject)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyRotation(t *testing.T) {
+	k1, k2, k3 := newSigningKey(t, "k1"), newSigningKey(t, "k2"), newSigningKey(t, "k3")
+
+	src := &stubSource{data: encodeJWKS(t, k1)}
+	v := newTestVerifier(t, src)
+	fetches := src.fetches
+
+	rotated := sign(t, jwt.SigningMethodEdDSA, k2.private, "k2", claimsFor("42", time.Hour))
+	src.data = encodeJWKS(t, k1, k2)
+
+	_, err := v.Verify(context.Background(), rotated)
+	requireUnauthenticated(t, err, AccessTokenInvalidError)
+
+	if src.fetches != fetches {
+		t.Fatalf("unknown kid refreshed keys %d times within the throttle interval", src.fetches-fetches)
+	}
+
+	v.lastRefresh = time.Now().Add(-minRefreshInterval)
+
+	if _, err = v.Verify(context.Background(), rotated); err != nil {
+		t.Fatalf("rotated key was not picked up: %v", err)
+	}
+
+	if src.fetches != fetches+1 {
+		t.Fatalf("fetched keys %d times, want 1", src.fetches-fetches)
+	}
+
+	_, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodEdDSA, k3.private, "k3", claimsFor("42", time.Hour)))
+	requireUnauthenticated(t, err, AccessTokenInvalidError)
+
+	if src.fetches != fetches+1 {
+		t.Fatal("unknown kid refreshed keys right after a refresh")
+	}
+}
+
+func requireUnauthenticated(t *testing.T, err error, message string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	if err == nil || st.Code() != codes.Unauthenticated || st.Message() != message {
+		t.Fatalf("error = %v, want %s", err, message)
+	}
+}
@@ -0,0 +1,20 @@
+package breaker
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AdminHandler lists the state and rolling window statistics of every breaker.
+func (s *Set) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Snapshot())
+	})
+}
@@ -0,0 +1,226 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+const OpenError = "upstream circuit breaker is open"
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+type bucket struct {
+	epoch    int64
+	total    int
+	failures int
+	slow     int
+}
+
+// Stats summarizes the calls recorded in the rolling window.
+type Stats struct {
+	Total        int     `json:"total"`
+	Failures     int     `json:"failures"`
+	Slow         int     `json:"slow"`
+	FailureRate  float64 `json:"failure_rate"`
+	SlowCallRate float64 `json:"slow_call_rate"`
+}
+
+// Breaker guards one upstream, or one method of it.
+type Breaker struct {
+	upstream string
+	method   string
+	opts     *Options
+	state    State
+	openedAt time.Time
+	buckets  []bucket
+	width    time.Duration
+	probes   int
+	passed   int
+	mx       sync.Mutex
+	logger   *zap.Logger
+}
+
+func newBreaker(upstream, method string, opts *Options, logger *zap.Logger) *Breaker {
+	b := &Breaker{
+		upstream: upstream,
+		method:   method,
+		opts:     opts,
+		buckets:  make([]bucket, opts.Buckets),
+		width:    opts.Window / time.Duration(opts.Buckets),
+		logger:   logger,
+	}
+
+	b.publish()
+
+	return b
+}
+
+// Allow reports whether a call may go upstream.
+func (b *Breaker) Allow(ctx context.Context) error {
+	now := time.Now()
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.opts.OpenDuration {
+			rejectedCounter.WithLabelValues(b.upstream, b.method).Inc()
+			return status.Error(codes.Unavailable, OpenError)
+		}
+
+		b.transition(ctx, StateHalfOpen, now)
+	case StateHalfOpen:
+		if b.probes >= b.opts.HalfOpenRequests {
+			rejectedCounter.WithLabelValues(b.upstream, b.method).Inc()
+			return status.Error(codes.Unavailable, OpenError)
+		}
+	}
+
+	if b.state == StateHalfOpen {
+		b.probes++
+	}
+
+	return nil
+}
+
+func (b *Breaker) Record(ctx context.Context, code codes.Code, elapsed time.Duration) {
+	now := time.Now()
+	failed := b.opts.isFailure(code)
+	slow := b.opts.SlowCallDuration > 0 && elapsed >= b.opts.SlowCallDuration
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		if failed || slow {
+			b.transition(ctx, StateOpen, now)
+			return
+		}
+
+		b.passed++
+		if b.passed >= b.opts.HalfOpenRequests {
+			b.transition(ctx, StateClosed, now)
+		}
+	case StateClosed:
+		bk := b.bucket(now)
+		bk.total++
+
+		if failed {
+			bk.failures++
+		}
+
+		if slow {
+			bk.slow++
+		}
+
+		stats := b.stats(now)
+		b.publishStats(stats)
+
+		if stats.Total < b.opts.MinRequests {
+			return
+		}
+
+		if stats.FailureRate >= b.opts.FailureRate || stats.SlowCallRate >= b.opts.SlowCallRate {
+			b.transition(ctx, StateOpen, now)
+		}
+	}
+}
+
+func (b *Breaker) State() State {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) transition(ctx context.Context, to State, now time.Time) {
+	from := b.state
+
+	b.state = to
+	b.probes = 0
+	b.passed = 0
+
+	switch to {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		clear(b.buckets)
+	}
+
+	b.publish()
+
+	requestid.Logger(ctx, b.logger).Warn("circuit breaker state changed",
+		zap.String("upstream", b.upstream),
+		zap.String("method", b.method),
+		zap.Stringer("from", from),
+		zap.Stringer("to", to),
+	)
+}
+
+func (b *Breaker) bucket(now time.Time) *bucket {
+	epoch := now.UnixNano() / int64(b.width)
+
+	bk := &b.buckets[epoch%int64(len(b.buckets))]
+	if bk.epoch != epoch {
+		*bk = bucket{epoch: epoch}
+	}
+
+	return bk
+}
+
+func (b *Breaker) stats(now time.Time) Stats {
+	oldest := now.UnixNano()/int64(b.width) - int64(len(b.buckets))
+
+	var s Stats
+	for _, bk := range b.buckets {
+		if bk.epoch <= oldest {
+			continue
+		}
+
+		s.Total += bk.total
+		s.Failures += bk.failures
+		s.Slow += bk.slow
+	}
+
+	if s.Total > 0 {
+		s.FailureRate = float64(s.Failures) / float64(s.Total)
+		s.SlowCallRate = float64(s.Slow) / float64(s.Total)
+	}
+
+	return s
+}
+
+func (b *Breaker) publish() {
+	stateGauge.WithLabelValues(b.upstream, b.method).Set(float64(b.state))
+}
+
+func (b *Breaker) publishStats(s Stats) {
+	failureRateGauge.WithLabelValues(b.upstream, b.method).Set(s.FailureRate)
+	slowCallRateGauge.WithLabelValues(b.upstream, b.method).Set(s.SlowCallRate)
+}
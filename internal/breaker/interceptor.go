@@ -0,0 +1,105 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Resolver names the upstream a method is currently routed to.
+type Resolver interface {
+	Upstream(fullMethod string) (string, bool)
+}
+
+// Deadlines tells deadlines the caller set apart from the timeout policy.
+type Deadlines interface {
+	CallerImposed(ctx context.Context, fullMethod string) bool
+}
+
+// UnaryClientInterceptor must run after rbac and the binder, directly before the deadline interceptor.
+func (s *Set) UnaryClientInterceptor(upstream string, deadlines Deadlines) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		b := s.Get(upstream, method)
+		if err := b.Allow(ctx); err != nil {
+			return err
+		}
+
+		callerDeadline := deadlines.CallerImposed(ctx, method)
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.Record(ctx, outcome(ctx, err, callerDeadline), time.Since(start))
+
+		return err
+	}
+}
+
+// StreamServerInterceptor times streams to their first response.
+func (s *Set) StreamServerInterceptor(resolver Resolver, deadlines Deadlines) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		upstream, ok := resolver.Upstream(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+
+		b := s.Get(upstream, info.FullMethod)
+		if err := b.Allow(ss.Context()); err != nil {
+			return err
+		}
+
+		callerDeadline := deadlines.CallerImposed(ss.Context(), info.FullMethod)
+
+		stream := &timedStream{ServerStream: ss, start: time.Now()}
+		err := handler(srv, stream)
+		b.Record(ss.Context(), outcome(ss.Context(), err, callerDeadline), stream.elapsed())
+
+		return err
+	}
+}
+
+// outcome does not blame the upstream for caller deadlines and cancellations.
+func outcome(ctx context.Context, err error, callerDeadline bool) codes.Code {
+	code := status.Code(err)
+
+	switch {
+	case err != nil && ctx.Err() == context.Canceled:
+		return codes.Canceled
+	case code == codes.DeadlineExceeded && callerDeadline:
+		return codes.Canceled
+	default:
+		return code
+	}
+}
+
+type timedStream struct {
+	grpc.ServerStream
+	start time.Time
+	first time.Duration
+	mx    sync.Mutex
+}
+
+func (s *timedStream) SendMsg(m any) error {
+	s.mx.Lock()
+	if s.first == 0 {
+		s.first = time.Since(s.start)
+	}
+	s.mx.Unlock()
+
+	return s.ServerStream.SendMsg(m)
+}
+
+// elapsed is the time to the first response, or the whole call when none was sent.
+func (s *timedStream) elapsed() time.Duration {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.first > 0 {
+		return s.first
+	}
+
+	return time.Since(s.start)
+}
@@ -0,0 +1,46 @@
+package breaker
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	stateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_circuit_breaker_state",
+			Help: "Circuit breaker state per upstream and method (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"upstream", "method"},
+	)
+
+	failureRateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_circuit_breaker_failure_rate",
+			Help: "Failure rate over the circuit breaker rolling window",
+		},
+		[]string{"upstream", "method"},
+	)
+
+	slowCallRateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_circuit_breaker_slow_call_rate",
+			Help: "Slow call rate over the circuit breaker rolling window",
+		},
+		[]string{"upstream", "method"},
+	)
+
+	rejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_circuit_breaker_rejected_total",
+			Help: "Total number of calls rejected by an open circuit breaker",
+		},
+		[]string{"upstream", "method"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		stateGauge,
+		failureRateGauge,
+		slowCallRateGauge,
+		rejectedCounter,
+	)
+}
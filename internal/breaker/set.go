@@ -0,0 +1,173 @@
+package breaker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/grpccode"
+)
+
+type Options struct {
+	Window           time.Duration
+	Buckets          int
+	MinRequests      int
+	FailureRate      float64
+	SlowCallDuration time.Duration
+	SlowCallRate     float64
+	OpenDuration     time.Duration
+	HalfOpenRequests int
+	failureCodes     map[codes.Code]struct{}
+}
+
+func NewOptions(cfg *config.Breaker) (*Options, error) {
+	opts := &Options{
+		Window:           cfg.Window,
+		Buckets:          cfg.Buckets,
+		MinRequests:      cfg.MinRequests,
+		FailureRate:      cfg.FailureRate,
+		SlowCallDuration: cfg.SlowCallDuration,
+		SlowCallRate:     cfg.SlowCallRate,
+		OpenDuration:     cfg.OpenDuration,
+		HalfOpenRequests: cfg.HalfOpenRequests,
+		failureCodes:     make(map[codes.Code]struct{}, len(cfg.FailureCodes)),
+	}
+
+	switch {
+	case opts.Window <= 0 || opts.Buckets <= 0:
+		return nil, fmt.Errorf("window and buckets must be positive")
+	case opts.Window/time.Duration(opts.Buckets) <= 0:
+		return nil, fmt.Errorf("window %s is too short for %d buckets", opts.Window, opts.Buckets)
+	case opts.OpenDuration <= 0:
+		return nil, fmt.Errorf("open duration must be positive")
+	case opts.FailureRate <= 0 || opts.FailureRate > 1:
+		return nil, fmt.Errorf("failure rate must be within (0, 1]")
+	case opts.SlowCallRate <= 0 || opts.SlowCallRate > 1:
+		return nil, fmt.Errorf("slow call rate must be within (0, 1]")
+	}
+
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 1
+	}
+
+	if opts.HalfOpenRequests <= 0 {
+		opts.HalfOpenRequests = 1
+	}
+
+	for _, name := range cfg.FailureCodes {
+		code, ok := grpccode.Parse(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown status code %q", name)
+		}
+
+		opts.failureCodes[code] = struct{}{}
+	}
+
+	return opts, nil
+}
+
+func (o *Options) isFailure(code codes.Code) bool {
+	_, ok := o.failureCodes[code]
+	return ok
+}
+
+// Set keeps one breaker per upstream and per configured method.
+type Set struct {
+	opts     *Options
+	methods  map[string]struct{}
+	breakers map[string]*Breaker
+	mx       sync.Mutex
+	logger   *zap.Logger
+}
+
+func NewSet(cfg *config.Breaker, logger *zap.Logger) (*Set, error) {
+	opts, err := NewOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Set{
+		opts:     opts,
+		methods:  make(map[string]struct{}, len(cfg.Methods)),
+		breakers: make(map[string]*Breaker),
+		logger:   logger,
+	}
+
+	for _, m := range cfg.Methods {
+		s.methods[m] = struct{}{}
+	}
+
+	return s, nil
+}
+
+func (s *Set) Get(upstream, fullMethod string) *Breaker {
+	var method string
+	if _, ok := s.methods[fullMethod]; ok {
+		method = fullMethod
+	}
+
+	key := upstream + method
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	b, ok := s.breakers[key]
+	if !ok {
+		b = newBreaker(upstream, method, s.opts, s.logger)
+		s.breakers[key] = b
+	}
+
+	return b
+}
+
+type Status struct {
+	Upstream string    `json:"upstream"`
+	Method   string    `json:"method,omitempty"`
+	State    string    `json:"state"`
+	OpenedAt time.Time `json:"opened_at,omitzero"`
+	Stats    Stats     `json:"stats"`
+}
+
+func (s *Set) Snapshot() []Status {
+	s.mx.Lock()
+	breakers := make([]*Breaker, 0, len(s.breakers))
+	for _, b := range s.breakers {
+		breakers = append(breakers, b)
+	}
+	s.mx.Unlock()
+
+	now := time.Now()
+	statuses := make([]Status, 0, len(breakers))
+
+	for _, b := range breakers {
+		b.mx.Lock()
+		st := Status{
+			Upstream: b.upstream,
+			Method:   b.method,
+			State:    b.state.String(),
+			Stats:    b.stats(now),
+		}
+
+		if b.state != StateClosed {
+			st.OpenedAt = b.openedAt
+		}
+		b.mx.Unlock()
+
+		statuses = append(statuses, st)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Upstream != statuses[j].Upstream {
+			return statuses[i].Upstream < statuses[j].Upstream
+		}
+
+		return statuses[i].Method < statuses[j].Method
+	})
+
+	return statuses
+}
@@ -0,0 +1,411 @@
+package bridge
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+var connectCodes = map[codes.Code]struct {
+	name   string
+	status int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+type connectError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Details []connectDetail `json:"details,omitempty"`
+}
+
+type connectDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type connectEndStream struct {
+	Error    *connectError       `json:"error,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+func newConnectError(st *status.Status) *connectError {
+	e := &connectError{Code: connectCodes[st.Code()].name, Message: st.Message()}
+	if e.Code == "" {
+		e.Code = connectCodes[codes.Unknown].name
+	}
+
+	for _, detail := range st.Proto().GetDetails() {
+		e.Details = append(e.Details, connectDetail{
+			Type:  strings.TrimPrefix(detail.GetTypeUrl(), "type.googleapis.com/"),
+			Value: base64.RawStdEncoding.EncodeToString(detail.GetValue()),
+		})
+	}
+
+	return e
+}
+
+type codec struct {
+	json   bool
+	input  protoreflect.MessageType
+	output protoreflect.MessageType
+}
+
+func newCodec(fullMethod, subtype string) (*codec, error) {
+	switch subtype {
+	case "proto":
+		return &codec{}, nil
+	case "json":
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "unsupported codec %q", subtype)
+	}
+
+	md, err := registry.FindMethod(fullMethod)
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "json is not supported for %s", fullMethod)
+	}
+
+	c := &codec{json: true}
+
+	if c.input, err = protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName()); err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "json is not supported for %s", fullMethod)
+	}
+
+	if c.output, err = protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName()); err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "json is not supported for %s", fullMethod)
+	}
+
+	return c, nil
+}
+
+func (c *codec) request(data []byte) ([]byte, error) {
+	if !c.json {
+		return data, nil
+	}
+
+	msg := c.input.New().Interface()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+
+	return proto.Marshal(msg)
+}
+
+func (c *codec) response(data []byte) ([]byte, error) {
+	if !c.json {
+		return data, nil
+	}
+
+	msg := c.output.New().Interface()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid response: %v", err)
+	}
+
+	return protojson.Marshal(msg)
+}
+
+// Unary Connect trailers travel as "Trailer-" prefixed headers.
+func (h *Handler) serveConnectUnary(w http.ResponseWriter, r *http.Request, subtype string) {
+	c, err := newCodec(r.URL.Path, subtype)
+	if err != nil {
+		writeConnectError(w, nil, nil, status.Convert(err))
+		return
+	}
+
+	data, err := readConnectBody(r.Body, r.Header.Get("Content-Encoding"))
+	if err == nil {
+		data, err = c.request(data)
+	}
+
+	if err != nil {
+		writeConnectError(w, nil, nil, status.Convert(err))
+		return
+	}
+
+	req := grpcRequest(r, bytes.NewReader(frame(0, data)))
+	if err = connectTimeout(r, req); err != nil {
+		writeConnectError(w, nil, nil, status.Convert(err))
+		return
+	}
+
+	var md http.Header
+	var reply []byte
+
+	rec := newRecorder()
+	rec.onHeader = func(header http.Header) {
+		md = header
+	}
+	rec.onMessage = func(flags byte, data []byte) error {
+		if flags&flagCompressed != 0 {
+			return status.Error(codes.Internal, "compressed response")
+		}
+
+		reply = bytes.Clone(data)
+		return nil
+	}
+
+	h.server.ServeHTTP(rec, req)
+
+	trailer := rec.trailer()
+	st := rec.status()
+
+	if st.Code() == codes.OK {
+		reply, err = c.response(reply)
+		if err != nil {
+			st = status.Convert(err)
+		}
+	}
+
+	if st.Code() != codes.OK {
+		writeConnectError(w, md, trailer, st)
+		return
+	}
+
+	writeConnectHeaders(w, md, trailer)
+	w.Header().Set("Content-Type", "application/"+subtype)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(reply)
+}
+
+func writeConnectError(w http.ResponseWriter, md, trailer http.Header, st *status.Status) {
+	writeConnectHeaders(w, md, trailer)
+
+	code, ok := connectCodes[st.Code()]
+	if !ok {
+		code = connectCodes[codes.Unknown]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code.status)
+	_ = json.NewEncoder(w).Encode(newConnectError(st))
+}
+
+func writeConnectHeaders(w http.ResponseWriter, md, trailer http.Header) {
+	for key, values := range md {
+		w.Header()[key] = values
+	}
+
+	for key, values := range trailer {
+		if strings.HasPrefix(key, "Grpc-") {
+			continue
+		}
+
+		w.Header()["Trailer-"+key] = values
+	}
+}
+
+// serveConnectStream translates a streaming Connect call.
+func (h *Handler) serveConnectStream(w http.ResponseWriter, r *http.Request, subtype string) {
+	responseType := "application/connect+" + subtype
+	rc := http.NewResponseController(w)
+
+	c, err := newCodec(r.URL.Path, subtype)
+	if err != nil {
+		writeEndStream(w, rc, responseType, nil, status.Convert(err))
+		return
+	}
+
+	encoding := r.Header.Get("Connect-Content-Encoding")
+	if encoding != "" && encoding != "identity" && encoding != "gzip" {
+		writeEndStream(w, rc, responseType, nil, status.Newf(codes.Unimplemented, "unsupported encoding %q", encoding))
+		return
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		err := pipeEnvelopes(pw, r.Body, encoding, c)
+		close(done)
+		pw.CloseWithError(err)
+	}()
+
+	// The body must not be read once the handler has returned.
+	defer func() {
+		_ = pr.Close()
+
+		select {
+		case <-done:
+		default:
+			_ = rc.SetReadDeadline(time.Now())
+			_ = r.Body.Close()
+			<-done
+		}
+	}()
+
+	req := grpcRequest(r, pr)
+	if err = connectTimeout(r, req); err != nil {
+		writeEndStream(w, rc, responseType, nil, status.Convert(err))
+		return
+	}
+
+	rec := newRecorder()
+	rec.onHeader = func(md http.Header) {
+		for key, values := range md {
+			w.Header()[key] = values
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+	}
+	rec.onMessage = func(flags byte, data []byte) error {
+		if flags&flagCompressed != 0 {
+			return status.Error(codes.Internal, "compressed response")
+		}
+
+		data, err := c.response(data)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(frame(0, data))
+		return err
+	}
+	rec.onFlush = func() {
+		_ = rc.Flush()
+	}
+
+	h.server.ServeHTTP(rec, req)
+
+	headersSent := rec.wroteHeader && rec.failed == 0
+	if !headersSent {
+		writeEndStream(w, rc, responseType, rec.trailer(), rec.status())
+		return
+	}
+
+	writeEndStream(w, rc, "", rec.trailer(), rec.status())
+}
+
+// writeEndStream finishes a streaming response.
+func writeEndStream(w http.ResponseWriter, rc *http.ResponseController, responseType string, trailer http.Header, st *status.Status) {
+	if responseType != "" {
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	end := connectEndStream{Metadata: make(map[string][]string)}
+	if st.Code() != codes.OK {
+		end.Error = newConnectError(st)
+	}
+
+	for key, values := range trailer {
+		if !strings.HasPrefix(key, "Grpc-") {
+			end.Metadata[key] = values
+		}
+	}
+
+	data, _ := json.Marshal(end)
+	_, _ = w.Write(frame(flagEndStream, data))
+
+	if rc != nil {
+		_ = rc.Flush()
+	}
+}
+
+// pipeEnvelopes rewrites the request envelopes into uncompressed gRPC frames.
+func pipeEnvelopes(w io.Writer, body io.Reader, encoding string, c *codec) error {
+	for {
+		flags, data, err := readFrame(body)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if flags&flagCompressed != 0 {
+			if data, err = readConnectBody(bytes.NewReader(data), encoding); err != nil {
+				return err
+			}
+		}
+
+		if data, err = c.request(data); err != nil {
+			return err
+		}
+
+		if _, err = w.Write(frame(0, data)); err != nil {
+			return err
+		}
+	}
+}
+
+func readConnectBody(body io.Reader, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", "identity":
+	case "gzip":
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid gzip body: %v", err)
+		}
+
+		defer zr.Close()
+		body = zr
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "unsupported encoding %q", encoding)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxMessageSize+1))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error reading body: %v", err)
+	}
+
+	if len(data) > maxMessageSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "message exceeds the limit of %d bytes", maxMessageSize)
+	}
+
+	return data, nil
+}
+
+// connectTimeout carries Connect-Timeout-Ms over as grpc-timeout.
+func connectTimeout(r, req *http.Request) error {
+	v := r.Header.Get("Connect-Timeout-Ms")
+	if v == "" {
+		return nil
+	}
+
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ms < 0 || len(v) > 10 {
+		return status.Errorf(codes.InvalidArgument, "invalid Connect-Timeout-Ms %q", v)
+	}
+
+	// grpc-timeout allows at most eight digits.
+	if ms > 99999999 {
+		req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dS", ms/1000))
+	} else {
+		req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", ms))
+	}
+
+	return nil
+}
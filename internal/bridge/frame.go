@@ -0,0 +1,75 @@
+package bridge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	frameHeaderLen = 5
+
+	flagCompressed byte = 0x01
+	// flagTrailer marks the gRPC-Web trailer frame.
+	flagTrailer byte = 0x80
+	// flagEndStream marks the last Connect streaming envelope.
+	flagEndStream byte = 0x02
+
+	maxMessageSize = 4 << 20
+)
+
+// frame prefixes data the way all three protocols do.
+func frame(flags byte, data []byte) []byte {
+	out := make([]byte, frameHeaderLen+len(data))
+	out[0] = flags
+	binary.BigEndian.PutUint32(out[1:frameHeaderLen], uint32(len(data)))
+	copy(out[frameHeaderLen:], data)
+
+	return out
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [frameHeaderLen]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxMessageSize {
+		return 0, nil, fmt.Errorf("message of %d bytes exceeds the limit of %d", n, maxMessageSize)
+	}
+
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return 0, nil, err
+	}
+
+	return header[0], data, nil
+}
+
+func grpcRequest(r *http.Request, body io.Reader) *http.Request {
+	req := r.Clone(r.Context())
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2", 2, 0
+	req.Body = io.NopCloser(body)
+	req.ContentLength = -1
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Accept-Encoding")
+
+	for key := range req.Header {
+		if strings.HasPrefix(key, "Connect-") {
+			req.Header.Del(key)
+		}
+	}
+
+	return req
+}
@@ -0,0 +1,165 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Handler) serveGRPCWeb(w http.ResponseWriter, r *http.Request, contentType string) {
+	text := strings.HasPrefix(contentType, "application/grpc-web-text")
+	out := &webWriter{w: w, rc: http.NewResponseController(w), text: text}
+
+	responseType := "application/grpc-web+proto"
+	if text {
+		responseType = "application/grpc-web-text+proto"
+	}
+
+	var body io.Reader = r.Body
+
+	if text {
+		data, err := readText(r.Body)
+		if err != nil {
+			writeWebStatus(w, out, responseType, status.Convert(err))
+			return
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	rec := newRecorder()
+	rec.onHeader = func(md http.Header) {
+		for key, values := range md {
+			w.Header()[key] = values
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+	}
+	rec.onMessage = func(flags byte, data []byte) error {
+		return out.write(frame(flags, data))
+	}
+	rec.onFlush = out.flush
+
+	h.server.ServeHTTP(rec, grpcRequest(r, body))
+
+	if rec.failed != 0 {
+		writeWebStatus(w, out, responseType, rec.status())
+		return
+	}
+
+	// A call failing before any message still needs its headers.
+	rec.WriteHeader(http.StatusOK)
+
+	_ = out.write(frame(flagTrailer, encodeTrailer(rec.trailer())))
+	out.flush()
+}
+
+// writeWebStatus answers with a trailers-only response.
+func writeWebStatus(w http.ResponseWriter, out *webWriter, responseType string, st *status.Status) {
+	trailer := http.Header{}
+	trailer.Set("Grpc-Status", fmt.Sprintf("%d", st.Code()))
+	trailer.Set("Grpc-Message", url.PathEscape(st.Message()))
+
+	w.Header().Set("Content-Type", responseType)
+	w.WriteHeader(http.StatusOK)
+
+	_ = out.write(frame(flagTrailer, encodeTrailer(trailer)))
+	out.flush()
+}
+
+// gRPC-Web requires lower case trailer names.
+func encodeTrailer(trailer http.Header) []byte {
+	keys := make([]string, 0, len(trailer))
+	for key := range trailer {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, key := range keys {
+		for _, v := range trailer[key] {
+			fmt.Fprintf(&b, "%s: %s\r\n", strings.ToLower(key), v)
+		}
+	}
+
+	return b.Bytes()
+}
+
+// webWriter base64 encodes every flush as one padded chunk in text mode.
+type webWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	text    bool
+	pending []byte
+}
+
+func (ww *webWriter) write(p []byte) error {
+	if ww.text {
+		ww.pending = append(ww.pending, p...)
+		return nil
+	}
+
+	_, err := ww.w.Write(p)
+	return err
+}
+
+func (ww *webWriter) flush() {
+	if ww.text && len(ww.pending) > 0 {
+		_, _ = io.WriteString(ww.w, base64.StdEncoding.EncodeToString(ww.pending))
+		ww.pending = ww.pending[:0]
+	}
+
+	_ = ww.rc.Flush()
+}
+
+// readText reads and decodes a grpc-web-text body of at most one full-size frame.
+func readText(body io.Reader) ([]byte, error) {
+	limit := base64.StdEncoding.EncodedLen(frameHeaderLen + maxMessageSize)
+
+	data, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error reading body: %v", err)
+	}
+
+	if len(data) > limit {
+		return nil, status.Errorf(codes.ResourceExhausted, "body exceeds the limit of %d bytes", limit)
+	}
+
+	if data, err = decodeText(data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed grpc-web-text body: %v", err)
+	}
+
+	return data, nil
+}
+
+// decodeText decodes a grpc-web-text body.
+func decodeText(data []byte) ([]byte, error) {
+	data = bytes.Join(bytes.Fields(data), nil)
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("length %d is not a multiple of 4", len(data))
+	}
+
+	out := make([]byte, 0, base64.StdEncoding.DecodedLen(len(data)))
+
+	var quantum [3]byte
+	for ; len(data) > 0; data = data[4:] {
+		n, err := base64.StdEncoding.Decode(quantum[:], data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, quantum[:n]...)
+	}
+
+	return out, nil
+}
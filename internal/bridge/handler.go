@@ -0,0 +1,40 @@
+package bridge
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Handler serves gRPC-Web and Connect calls on the HTTP port.
+type Handler struct {
+	server http.Handler
+	next   http.Handler
+}
+
+func NewHandler(server, next http.Handler) *Handler {
+	return &Handler{
+		server: server,
+		next:   next,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch {
+	case strings.HasPrefix(contentType, "application/grpc-web"):
+		h.serveGRPCWeb(w, r, contentType)
+	case strings.HasPrefix(contentType, "application/connect+"):
+		h.serveConnectStream(w, r, strings.TrimPrefix(contentType, "application/connect+"))
+	case r.Header.Get("Connect-Protocol-Version") != "" && (contentType == "application/proto" || contentType == "application/json"):
+		h.serveConnectUnary(w, r, strings.TrimPrefix(contentType, "application/"))
+	default:
+		h.next.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,170 @@
+package bridge
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// recorder stands in for the HTTP/2 response writer the gRPC server expects.
+type recorder struct {
+	header      http.Header
+	wroteHeader bool
+	failed      int
+	buf         []byte
+	err         error
+
+	onHeader  func(md http.Header)
+	onMessage func(flags byte, data []byte) error
+	onFlush   func()
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		header:    make(http.Header),
+		onHeader:  func(http.Header) {},
+		onMessage: func(byte, []byte) error { return nil },
+		onFlush:   func() {},
+	}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+
+	// Only requests the server refuses outright get anything but 200.
+	if code != http.StatusOK {
+		r.failed = code
+		return
+	}
+
+	r.onHeader(r.metadata())
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+
+	if r.failed != 0 {
+		return len(p), nil
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	r.buf = append(r.buf, p...)
+
+	for len(r.buf) >= frameHeaderLen {
+		n := int(binary.BigEndian.Uint32(r.buf[1:frameHeaderLen]))
+		if len(r.buf) < frameHeaderLen+n {
+			break
+		}
+
+		if err := r.onMessage(r.buf[0], r.buf[frameHeaderLen:frameHeaderLen+n]); err != nil {
+			r.err = err
+			return 0, err
+		}
+
+		r.buf = r.buf[frameHeaderLen+n:]
+	}
+
+	return len(p), nil
+}
+
+func (r *recorder) Flush() {
+	r.WriteHeader(http.StatusOK)
+
+	if r.failed == 0 {
+		r.onFlush()
+	}
+}
+
+// metadata is the response header without the transport level fields.
+func (r *recorder) metadata() http.Header {
+	md := make(http.Header, len(r.header))
+
+	for key, values := range r.header {
+		switch {
+		case key == "Trailer", key == "Content-Type", key == "Date", strings.HasPrefix(key, http.TrailerPrefix):
+			continue
+		}
+
+		md[key] = values
+	}
+
+	return md
+}
+
+// trailer collects what the server meant to send as HTTP/2 trailers.
+func (r *recorder) trailer() http.Header {
+	trailer := make(http.Header)
+
+	for key, values := range r.header {
+		switch {
+		case strings.HasPrefix(key, http.TrailerPrefix):
+			trailer[http.CanonicalHeaderKey(strings.TrimPrefix(key, http.TrailerPrefix))] = values
+		case key == "Grpc-Status", key == "Grpc-Message", key == "Grpc-Status-Details-Bin":
+			trailer[key] = values
+		}
+	}
+
+	return trailer
+}
+
+// status falls back to the HTTP status for refused requests.
+func (r *recorder) status() *status.Status {
+	if r.failed != 0 {
+		return status.New(codes.Internal, http.StatusText(r.failed))
+	}
+
+	if r.err != nil {
+		return status.Convert(r.err)
+	}
+
+	trailer := r.trailer()
+
+	if details := trailer.Get("Grpc-Status-Details-Bin"); details != "" {
+		if data, err := decodeBinary(details); err == nil {
+			var st spb.Status
+			if proto.Unmarshal(data, &st) == nil {
+				return status.FromProto(&st)
+			}
+		}
+	}
+
+	code, err := strconv.Atoi(trailer.Get("Grpc-Status"))
+	if err != nil {
+		return status.New(codes.Unknown, "missing grpc-status")
+	}
+
+	message := trailer.Get("Grpc-Message")
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+
+	return status.New(codes.Code(code), message)
+}
+
+// decodeBinary reads "-bin" metadata, which may come with or without padding.
+func decodeBinary(v string) ([]byte, error) {
+	if len(v)%4 == 0 {
+		return base64.StdEncoding.DecodeString(v)
+	}
+
+	return base64.RawStdEncoding.DecodeString(v)
+}
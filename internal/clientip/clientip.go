@@ -0,0 +1,78 @@
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	ForwardedForHeader = "X-Forwarded-For"
+	RealIPHeader       = "X-Real-Ip"
+)
+
+// FromRequest returns the client address of r.
+func FromRequest(r *http.Request, trustForwarded bool) string {
+	if trustForwarded {
+		if ip := last(r.Header.Values(ForwardedForHeader)); ip != "" {
+			return ip
+		}
+
+		if ip := parse(r.Header.Get(RealIPHeader)); ip != "" {
+			return ip
+		}
+	}
+
+	return host(r.RemoteAddr)
+}
+
+// FromContext returns the client address of an incoming gRPC call.
+func FromContext(ctx context.Context, trustForwarded bool) string {
+	if trustForwarded {
+		values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(ForwardedForHeader))
+		if ip := last(values); ip != "" {
+			return ip
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return host(p.Addr.String())
+	}
+
+	return ""
+}
+
+// last returns the rightmost forwarded entry, the only one added by the trusted proxy.
+func last(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	list := values[len(values)-1]
+	if i := strings.LastIndexByte(list, ','); i >= 0 {
+		list = list[i+1:]
+	}
+
+	return parse(list)
+}
+
+func parse(value string) string {
+	ip := net.ParseIP(strings.TrimSpace(value))
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+func host(addr string) string {
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		return h
+	}
+
+	return addr
+}
@@ -0,0 +1,62 @@
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		forwarded []string
+		realIP    string
+		trust     bool
+		want      string
+	}{
+		{name: "untrusted", forwarded: []string{"203.0.113.7"}, want: "192.0.2.1"},
+		{name: "single hop", forwarded: []string{"203.0.113.7"}, trust: true, want: "203.0.113.7"},
+		{name: "spoofed leftmost", forwarded: []string{"198.51.100.9, 203.0.113.7"}, trust: true, want: "203.0.113.7"},
+		{name: "repeated header", forwarded: []string{"198.51.100.9", "203.0.113.7"}, trust: true, want: "203.0.113.7"},
+		{name: "invalid entry", forwarded: []string{"203.0.113.7, not-an-ip"}, trust: true, want: "192.0.2.1"},
+		{name: "real ip", realIP: " 2001:db8::1 ", trust: true, want: "2001:db8::1"},
+		{name: "invalid real ip", realIP: "unknown", trust: true, want: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:4321"
+
+			for _, v := range tt.forwarded {
+				r.Header.Add(ForwardedForHeader, v)
+			}
+
+			if tt.realIP != "" {
+				r.Header.Set(RealIPHeader, tt.realIP)
+			}
+
+			if got := FromRequest(r, tt.trust); got != tt.want {
+				t.Fatalf("FromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 4321}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "198.51.100.9, 203.0.113.7"))
+
+	if got := FromContext(ctx, true); got != "203.0.113.7" {
+		t.Fatalf("FromContext() = %q, want 203.0.113.7", got)
+	}
+
+	if got := FromContext(ctx, false); got != "192.0.2.1" {
+		t.Fatalf("FromContext() = %q, want 192.0.2.1", got)
+	}
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// AccessLog writes one entry per HTTP request and proxied gRPC call.
+type AccessLog struct {
+	Enabled       bool          `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	SampleRate    float64       `env:"SAMPLE_RATE" envDefault:"1" mapstructure:"sample_rate"`
+	SlowThreshold time.Duration `env:"SLOW_THRESHOLD" envDefault:"1s" mapstructure:"slow_threshold"`
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type Auth struct {
+	Enabled         bool          `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	Secret          string        `env:"SECRET" mapstructure:"secret"`
+	JWKSFile        string        `env:"JWKS_FILE" mapstructure:"jwks_file"`
+	JWKSURL         string        `env:"JWKS_URL" mapstructure:"jwks_url"`
+	RefreshInterval time.Duration `env:"JWKS_REFRESH_INTERVAL" envDefault:"5m" mapstructure:"jwks_refresh_interval"`
+	Leeway          time.Duration `env:"LEEWAY" envDefault:"30s" mapstructure:"leeway"`
+	Issuer          string        `env:"ISSUER" mapstructure:"issuer"`
+	Audience        string        `env:"AUDIENCE" mapstructure:"audience"`
+	Algorithms      []string      `env:"ALGORITHMS" envDefault:"HS256,RS256,EdDSA" mapstructure:"algorithms"`
+	PublicMethods   []string      `env:"PUBLIC_METHODS" mapstructure:"public_methods"`
+}
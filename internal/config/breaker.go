@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+type Breaker struct {
+	Enabled          bool          `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	Window           time.Duration `env:"WINDOW" envDefault:"30s" mapstructure:"window"`
+	Buckets          int           `env:"BUCKETS" envDefault:"10" mapstructure:"buckets"`
+	MinRequests      int           `env:"MIN_REQUESTS" envDefault:"20" mapstructure:"min_requests"`
+	FailureRate      float64       `env:"FAILURE_RATE" envDefault:"0.5" mapstructure:"failure_rate"`
+	SlowCallDuration time.Duration `env:"SLOW_CALL_DURATION" envDefault:"5s" mapstructure:"slow_call_duration"`
+	SlowCallRate     float64       `env:"SLOW_CALL_RATE" envDefault:"0.8" mapstructure:"slow_call_rate"`
+	OpenDuration     time.Duration `env:"OPEN_DURATION" envDefault:"30s" mapstructure:"open_duration"`
+	HalfOpenRequests int           `env:"HALF_OPEN_REQUESTS" envDefault:"5" mapstructure:"half_open_requests"`
+	FailureCodes     []string      `env:"FAILURE_CODES" envDefault:"Unavailable,DeadlineExceeded,Internal,Unknown" mapstructure:"failure_codes"`
+	Methods          []string      `env:"METHODS" mapstructure:"methods"`
+}
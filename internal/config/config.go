@@ -1,7 +1,125 @@
 package config
 
-import "github.com/QuizWars-Ecosystem/go-common/pkg/config"
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/QuizWars-Ecosystem/go-common/pkg/config"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	config.DefaultGatewayConfig
+	ConfigPath        string     `env:"CONFIG_PATH"`
+	ConfigKVPrefix    string     `env:"CONFIG_CONSUL_PREFIX"`
+	SinglePort        bool       `env:"SINGLE_PORT" mapstructure:"single_port"`
+	HTTPTLS           TLS        `envPrefix:"HTTP_TLS_" mapstructure:"http_tls"`
+	GRPCTLS           TLS        `envPrefix:"GRPC_TLS_" mapstructure:"grpc_tls"`
+	TrustForwardedFor bool       `env:"TRUST_FORWARDED_FOR" mapstructure:"trust_forwarded_for"`
+	HTTPRulesPath     string     `env:"HTTP_RULES_PATH" mapstructure:"http_rules_path"`
+	Auth              Auth       `envPrefix:"AUTH_" mapstructure:"auth"`
+	RateLimit         RateLimit  `envPrefix:"RATE_LIMIT_" mapstructure:"rate_limit"`
+	Lockout           Lockout    `envPrefix:"LOCKOUT_" mapstructure:"lockout"`
+	Timeouts          Timeouts   `envPrefix:"TIMEOUTS_" mapstructure:"timeouts"`
+	Breaker           Breaker    `envPrefix:"BREAKER_" mapstructure:"breaker"`
+	CORS              CORS       `envPrefix:"CORS_" mapstructure:"cors"`
+	Drain             Drain      `envPrefix:"DRAIN_" mapstructure:"drain"`
+	AccessLog         AccessLog  `envPrefix:"ACCESS_LOG_" mapstructure:"access_log"`
+	Redaction         Redaction  `envPrefix:"REDACTION_" mapstructure:"redaction"`
+	Tracing           Tracing    `envPrefix:"TRACING_" mapstructure:"tracing"`
+	Metrics           Metrics    `envPrefix:"METRICS_" mapstructure:"metrics"`
+	Upstreams         []Upstream `envPrefix:"UPSTREAMS" mapstructure:"upstreams"`
+}
+
+// Load overlays the YAML file and the Consul prefix on the environment.
+func Load() (*Config, error) {
+	cfg, err := loadLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ConfigKVPrefix == "" {
+		return cfg, nil
+	}
+
+	pairs, err := fetchKV(cfg.ConsulURL, cfg.ConfigKVPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = overlayKV(cfg, pairs); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadLocal() (*Config, error) {
+	cfg, err := config.Load[Config]()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ConfigPath == "" {
+		return cfg, nil
+	}
+
+	if err = overlay(cfg, cfg.ConfigPath); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func overlay(cfg *Config, path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return unmarshal(v, cfg)
+}
+
+func overlayYAML(cfg *Config, data []byte) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return unmarshal(v, cfg)
+}
+
+// gatewayConfig gives the embedded go-common settings their snake_case keys.
+type gatewayConfig struct {
+	Local           bool          `mapstructure:"local"`
+	LogLevel        string        `mapstructure:"log_level"`
+	HTTPPort        string        `mapstructure:"http_port"`
+	TCPPort         string        `mapstructure:"tcp_port"`
+	GRPCPort        string        `mapstructure:"grpc_port"`
+	WSPort          string        `mapstructure:"ws_port"`
+	StartTimeout    time.Duration `mapstructure:"start_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	ConsulURL       string        `mapstructure:"consul_url"`
+}
+
+func unmarshal(v *viper.Viper, cfg *Config) error {
+	if err := v.Unmarshal(cfg, func(dc *mapstructure.DecoderConfig) { dc.Squash = true }); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	gateway := gatewayConfig(cfg.DefaultGatewayConfig)
+	if err := v.Unmarshal(&gateway); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	cfg.DefaultGatewayConfig = config.DefaultGatewayConfig(gateway)
+
+	return nil
 }
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// CORS holds the policy applied to every path, and per route prefix overrides.
+type CORS struct {
+	Enabled          bool          `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	AllowedOrigins   []string      `env:"ALLOWED_ORIGINS" mapstructure:"allowed_origins"`
+	AllowedMethods   []string      `env:"ALLOWED_METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE" mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `env:"ALLOWED_HEADERS" envDefault:"Authorization,Content-Type,X-Grpc-Web,X-User-Agent,Grpc-Timeout,Connect-Protocol-Version,Connect-Timeout-Ms,X-Request-ID,X-Api-Key" mapstructure:"allowed_headers"`
+	ExposedHeaders   []string      `env:"EXPOSED_HEADERS" envDefault:"Grpc-Status,Grpc-Message,Grpc-Status-Details-Bin,X-Request-ID,Retry-After,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset" mapstructure:"exposed_headers"`
+	AllowCredentials bool          `env:"ALLOW_CREDENTIALS" mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `env:"MAX_AGE" envDefault:"10m" mapstructure:"max_age"`
+	Routes           []CORSRoute   `envPrefix:"ROUTES" mapstructure:"routes"`
+}
+
+type CORSRoute struct {
+	Prefix           string        `env:"PREFIX" mapstructure:"prefix"`
+	AllowedOrigins   []string      `env:"ALLOWED_ORIGINS" mapstructure:"allowed_origins"`
+	AllowedMethods   []string      `env:"ALLOWED_METHODS" mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `env:"ALLOWED_HEADERS" mapstructure:"allowed_headers"`
+	ExposedHeaders   []string      `env:"EXPOSED_HEADERS" mapstructure:"exposed_headers"`
+	AllowCredentials *bool         `env:"ALLOW_CREDENTIALS" mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `env:"MAX_AGE" mapstructure:"max_age"`
+}
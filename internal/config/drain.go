@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+type Drain struct {
+	Delay   time.Duration `env:"DELAY" envDefault:"3s" mapstructure:"delay"`
+	Timeout time.Duration `env:"TIMEOUT" envDefault:"10s" mapstructure:"timeout"`
+}
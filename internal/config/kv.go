@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const kvTimeout = 10 * time.Second
+
+func newConsulClient(address string) (*api.Client, error) {
+	consulCfg := api.DefaultConfig()
+	consulCfg.Address = address
+
+	client, err := api.NewClient(consulCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating consul client: %w", err)
+	}
+
+	return client, nil
+}
+
+func fetchKV(address, prefix string) (api.KVPairs, error) {
+	client, err := newConsulClient(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kvTimeout)
+	defer cancel()
+
+	pairs, _, err := client.KV().List(prefix, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error reading consul prefix %s: %w", prefix, err)
+	}
+
+	return pairs, nil
+}
+
+// overlayKV applies every YAML document under the prefix in key order.
+func overlayKV(cfg *Config, pairs api.KVPairs) error {
+	for _, pair := range pairs {
+		if len(bytes.TrimSpace(pair.Value)) == 0 {
+			continue
+		}
+
+		if err := overlayYAML(cfg, pair.Value); err != nil {
+			return fmt.Errorf("consul key %s: %w", pair.Key, err)
+		}
+	}
+
+	return nil
+}
+
+func equalKV(a, b api.KVPairs) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Key != b[i].Key || !bytes.Equal(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type Lockout struct {
+	Enabled         bool          `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	DelayAfter      int           `env:"DELAY_AFTER" envDefault:"3" mapstructure:"delay_after"`
+	BaseDelay       time.Duration `env:"BASE_DELAY" envDefault:"1s" mapstructure:"base_delay"`
+	MaxDelay        time.Duration `env:"MAX_DELAY" envDefault:"30s" mapstructure:"max_delay"`
+	LockAfter       int           `env:"LOCK_AFTER" envDefault:"10" mapstructure:"lock_after"`
+	IPLockAfter     int           `env:"IP_LOCK_AFTER" envDefault:"50" mapstructure:"ip_lock_after"`
+	LockDuration    time.Duration `env:"LOCK_DURATION" envDefault:"5m" mapstructure:"lock_duration"`
+	MaxLockDuration time.Duration `env:"MAX_LOCK_DURATION" envDefault:"1h" mapstructure:"max_lock_duration"`
+	ResetAfter      time.Duration `env:"RESET_AFTER" envDefault:"1h" mapstructure:"reset_after"`
+	FailureCodes    []string      `env:"FAILURE_CODES" envDefault:"Unauthenticated,NotFound,PermissionDenied" mapstructure:"failure_codes"`
+}
@@ -0,0 +1,7 @@
+package config
+
+type Metrics struct {
+	Buckets     []float64 `env:"BUCKETS" envDefault:"0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10" mapstructure:"buckets"`
+	SizeBuckets []float64 `env:"SIZE_BUCKETS" envDefault:"64,256,1024,4096,16384,65536,262144,1048576" mapstructure:"size_buckets"`
+	Exemplars   bool      `env:"EXEMPLARS" envDefault:"true" mapstructure:"exemplars"`
+}
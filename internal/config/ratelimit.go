@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type RateLimit struct {
+	Enabled  bool            `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	Store    string          `env:"STORE" envDefault:"memory" mapstructure:"store"`
+	RedisURL string          `env:"REDIS_URL" mapstructure:"redis_url"`
+	APIKeys  []string        `env:"API_KEYS" mapstructure:"api_keys"`
+	Rules    []RateLimitRule `envPrefix:"RULES" mapstructure:"rules"`
+}
+
+type RateLimitRule struct {
+	Name      string        `env:"NAME" mapstructure:"name"`
+	Methods   []string      `env:"METHODS" mapstructure:"methods"`
+	Key       string        `env:"KEY" mapstructure:"key"`
+	Algorithm string        `env:"ALGORITHM" mapstructure:"algorithm"`
+	Limit     int           `env:"LIMIT" mapstructure:"limit"`
+	Window    time.Duration `env:"WINDOW" mapstructure:"window"`
+	Burst     int           `env:"BURST" mapstructure:"burst"`
+}
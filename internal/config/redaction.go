@@ -0,0 +1,8 @@
+package config
+
+// Redaction extends the built-in list of sensitive data scrubbed from logs and traces.
+type Redaction struct {
+	Fields    []string `env:"FIELDS" mapstructure:"fields"`
+	JSONPaths []string `env:"JSON_PATHS" mapstructure:"json_paths"`
+	Headers   []string `env:"HEADERS" mapstructure:"headers"`
+}
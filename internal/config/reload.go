@@ -0,0 +1,284 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/api/watch"
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/logging"
+	"github.com/QuizWars-Ecosystem/go-common/pkg/abstractions"
+)
+
+const reloadDebounce = 200 * time.Millisecond
+
+// Reloader re-reads the configuration on SIGHUP, file and Consul changes.
+type Reloader struct {
+	initial     *Config
+	current     *Config
+	kv          api.KVPairs
+	kvLoaded    bool
+	subscribers []abstractions.ConfigSubscriber[*Config]
+	logger      *logging.Logger
+	mx          sync.Mutex
+}
+
+func NewReloader(cfg *Config, logger *logging.Logger) *Reloader {
+	return &Reloader{
+		initial: cfg,
+		current: cfg,
+		logger:  logger,
+	}
+}
+
+func (r *Reloader) Subscribe(subscriber abstractions.ConfigSubscriber[*Config]) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.subscribers = append(r.subscribers, subscriber)
+}
+
+func (r *Reloader) Config() *Config {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.current
+}
+
+// Reload loads a fresh revision and applies it.
+func (r *Reloader) Reload() error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	next, err := loadLocal()
+	if err != nil {
+		return err
+	}
+
+	if prefix := r.initial.ConfigKVPrefix; prefix != "" {
+		if !r.kvLoaded {
+			if r.kv, err = fetchKV(r.initial.ConsulURL, prefix); err != nil {
+				return err
+			}
+
+			r.kvLoaded = true
+		}
+
+		if err = overlayKV(next, r.kv); err != nil {
+			return err
+		}
+	}
+
+	if fields := RestartRequired(r.initial, next); len(fields) > 0 {
+		r.logger.Zap().Warn("configuration changes require restart", zap.Strings("fields", fields))
+	}
+
+	for _, subscriber := range r.subscribers {
+		if err = subscriber.UpdateConfig(next); err != nil {
+			return fmt.Errorf("error applying %s config: %w", subscriber.SectionKey(), err)
+		}
+	}
+
+	r.current = next
+
+	return nil
+}
+
+func (r *Reloader) Watch(ctx context.Context) error {
+	if r.initial.ConfigPath != "" {
+		if err := r.watchFile(ctx, r.initial.ConfigPath); err != nil {
+			return err
+		}
+	}
+
+	if r.initial.ConfigKVPrefix != "" {
+		if err := r.watchPrefix(ctx, r.initial.ConfigKVPrefix); err != nil {
+			return err
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				r.reload("signal")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *Reloader) reload(trigger string) {
+	logger := r.logger.Zap().With(zap.String("trigger", trigger))
+
+	if err := r.Reload(); err != nil {
+		logger.Error("error reloading configuration", zap.Error(err))
+		return
+	}
+
+	logger.Info("configuration reloaded")
+}
+
+// The directory is watched so atomic renames and config map swaps count.
+func (r *Reloader) watchFile(ctx context.Context, path string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating config watcher: %w", err)
+	}
+
+	path = filepath.Clean(path)
+
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("error watching config file: %w", err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var pending <-chan time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				name := filepath.Clean(event.Name)
+				if name != path && filepath.Base(name) != "..data" || event.Has(fsnotify.Chmod) {
+					continue
+				}
+
+				pending = time.After(reloadDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				r.logger.Zap().Warn("config watch error", zap.Error(err))
+			case <-pending:
+				pending = nil
+				r.reload("file")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *Reloader) watchPrefix(ctx context.Context, prefix string) error {
+	plan, err := watch.Parse(map[string]interface{}{
+		"type":   "keyprefix",
+		"prefix": prefix,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating consul prefix watch: %w", err)
+	}
+
+	plan.Handler = func(_ uint64, data interface{}) {
+		pairs, _ := data.(api.KVPairs)
+
+		r.mx.Lock()
+		changed := !r.kvLoaded || !equalKV(r.kv, pairs)
+		r.kv, r.kvLoaded = pairs, true
+		r.mx.Unlock()
+
+		if changed {
+			r.reload("consul")
+		}
+	}
+
+	client, err := newConsulClient(r.initial.ConsulURL)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		plan.Stop()
+	}()
+
+	go func() {
+		if err := plan.RunWithClientAndHclog(client, r.logger.HCLogger()); err != nil {
+			r.logger.Zap().Warn("consul prefix watch error", zap.String("prefix", prefix), zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// RestartRequired lists changed settings that are only read at startup.
+func RestartRequired(prev, next *Config) []string {
+	var fields []string
+
+	check := func(name string, a, b any) {
+		if !reflect.DeepEqual(a, b) {
+			fields = append(fields, name)
+		}
+	}
+
+	check("local", prev.Local, next.Local)
+	check("http_port", prev.HTTPPort, next.HTTPPort)
+	check("tcp_port", prev.TCPPort, next.TCPPort)
+	check("grpc_port", prev.GRPCPort, next.GRPCPort)
+	check("ws_port", prev.WSPort, next.WSPort)
+	check("single_port", prev.SinglePort, next.SinglePort)
+	check("http_tls", prev.HTTPTLS, next.HTTPTLS)
+	check("grpc_tls", prev.GRPCTLS, next.GRPCTLS)
+	check("start_timeout", prev.StartTimeout, next.StartTimeout)
+	check("shutdown_timeout", prev.ShutdownTimeout, next.ShutdownTimeout)
+	check("consul_url", prev.ConsulURL, next.ConsulURL)
+	check("trust_forwarded_for", prev.TrustForwardedFor, next.TrustForwardedFor)
+	check("http_rules_path", prev.HTTPRulesPath, next.HTTPRulesPath)
+	check("auth", staticAuth(prev.Auth), staticAuth(next.Auth))
+	check("rate_limit.enabled", prev.RateLimit.Enabled, next.RateLimit.Enabled)
+	check("rate_limit.store", prev.RateLimit.Store, next.RateLimit.Store)
+	check("rate_limit.redis_url", prev.RateLimit.RedisURL, next.RateLimit.RedisURL)
+	check("lockout", prev.Lockout, next.Lockout)
+	check("breaker", prev.Breaker, next.Breaker)
+	check("cors.enabled", prev.CORS.Enabled, next.CORS.Enabled)
+	check("drain", prev.Drain, next.Drain)
+	check("access_log.enabled", prev.AccessLog.Enabled, next.AccessLog.Enabled)
+	check("redaction", prev.Redaction, next.Redaction)
+	check("tracing", prev.Tracing, next.Tracing)
+	check("metrics", prev.Metrics, next.Metrics)
+	check("upstreams", staticUpstreams(prev.Upstreams), staticUpstreams(next.Upstreams))
+
+	return fields
+}
+
+func staticAuth(auth Auth) Auth {
+	auth.PublicMethods = nil
+	return auth
+}
+
+func staticUpstreams(upstreams []Upstream) map[string]Upstream {
+	static := make(map[string]Upstream, len(upstreams))
+	for _, upstream := range upstreams {
+		upstream.Services = nil
+		static[upstream.Name] = upstream
+	}
+
+	return static
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/logging"
+)
+
+type subscriber struct {
+	cfg *Config
+}
+
+func (s *subscriber) SectionKey() string {
+	return "test"
+}
+
+func (s *subscriber) UpdateConfig(cfg *Config) error {
+	s.cfg = cfg
+	return nil
+}
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadGatewayKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "log_level: warn\nhttp_port: \"8100\"\nstart_timeout: 5s\n")
+	t.Setenv("CONFIG_PATH", path)
+
+	initial, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if initial.LogLevel != "warn" || initial.HTTPPort != "8100" || initial.StartTimeout.String() != "5s" {
+		t.Fatalf("file keys were not applied: %+v", initial.DefaultGatewayConfig)
+	}
+
+	writeConfig(t, path, "log_level: debug\nhttp_port: \"9000\"\nstart_timeout: 5s\n")
+
+	sub := &subscriber{}
+	r := NewReloader(initial, logging.NewLogger(true, "info"))
+	r.Subscribe(sub)
+
+	if err = r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sub.cfg.LogLevel != "debug" {
+		t.Fatalf("log_level = %q, want debug", sub.cfg.LogLevel)
+	}
+
+	if sub.cfg.HTTPPort != "9000" {
+		t.Fatalf("http_port = %q, want 9000", sub.cfg.HTTPPort)
+	}
+
+	fields := RestartRequired(initial, sub.cfg)
+	if !slices.Equal(fields, []string{"http_port"}) {
+		t.Fatalf("restart required for %v, want [http_port]", fields)
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type Timeouts struct {
+	Default time.Duration `env:"DEFAULT" envDefault:"15s" mapstructure:"default"`
+	Rules   []TimeoutRule `envPrefix:"RULES" mapstructure:"rules"`
+}
+
+type TimeoutRule struct {
+	Methods []string      `env:"METHODS" mapstructure:"methods"`
+	Timeout time.Duration `env:"TIMEOUT" mapstructure:"timeout"`
+}
@@ -0,0 +1,12 @@
+package config
+
+// TLS configures a listener.
+type TLS struct {
+	Enabled      bool     `env:"ENABLED" mapstructure:"enabled"`
+	CertFile     string   `env:"CERT_FILE" mapstructure:"cert_file"`
+	KeyFile      string   `env:"KEY_FILE" mapstructure:"key_file"`
+	MinVersion   string   `env:"MIN_VERSION" envDefault:"1.2" mapstructure:"min_version"`
+	CipherSuites []string `env:"CIPHER_SUITES" mapstructure:"cipher_suites"`
+	ClientAuth   string   `env:"CLIENT_AUTH" envDefault:"none" mapstructure:"client_auth"`
+	ClientCAFile string   `env:"CLIENT_CA_FILE" mapstructure:"client_ca_file"`
+}
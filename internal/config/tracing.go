@@ -0,0 +1,13 @@
+package config
+
+// Tracing exports spans over OTLP, Protocol being "grpc" or "http".
+type Tracing struct {
+	Enabled     bool              `env:"ENABLED" envDefault:"true" mapstructure:"enabled"`
+	ServiceName string            `env:"SERVICE_NAME" envDefault:"gateway" mapstructure:"service_name"`
+	Endpoint    string            `env:"ENDPOINT" envDefault:"otel-collector:4317" mapstructure:"endpoint"`
+	Protocol    string            `env:"PROTOCOL" envDefault:"grpc" mapstructure:"protocol"`
+	Insecure    bool              `env:"INSECURE" envDefault:"true" mapstructure:"insecure"`
+	Headers     map[string]string `env:"HEADERS" mapstructure:"headers"`
+	SampleRatio float64           `env:"SAMPLE_RATIO" envDefault:"0.1" mapstructure:"sample_ratio"`
+	ParentBased bool              `env:"PARENT_BASED" envDefault:"true" mapstructure:"parent_based"`
+}
@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// Upstream routes Services to the Consul service Name.
+type Upstream struct {
+	Name              string        `env:"NAME" mapstructure:"name"`
+	Services          []string      `env:"SERVICES" mapstructure:"services"`
+	Optional          bool          `env:"OPTIONAL" mapstructure:"optional"`
+	Dial              Dial          `envPrefix:"DIAL_" mapstructure:"dial"`
+	Retries           []RetryPolicy `envPrefix:"RETRIES" mapstructure:"retries"`
+	IdempotentMethods []string      `env:"IDEMPOTENT_METHODS" mapstructure:"idempotent_methods"`
+}
+
+type RetryPolicy struct {
+	Methods           []string      `env:"METHODS" mapstructure:"methods"`
+	MaxAttempts       int           `env:"MAX_ATTEMPTS" mapstructure:"max_attempts"`
+	InitialBackoff    time.Duration `env:"INITIAL_BACKOFF" mapstructure:"initial_backoff"`
+	MaxBackoff        time.Duration `env:"MAX_BACKOFF" mapstructure:"max_backoff"`
+	BackoffMultiplier float64       `env:"BACKOFF_MULTIPLIER" mapstructure:"backoff_multiplier"`
+	RetryableCodes    []string      `env:"RETRYABLE_CODES" mapstructure:"retryable_codes"`
+	HedgingDelay      time.Duration `env:"HEDGING_DELAY" mapstructure:"hedging_delay"`
+	NonFatalCodes     []string      `env:"NON_FATAL_CODES" mapstructure:"non_fatal_codes"`
+	Idempotent        bool          `env:"IDEMPOTENT" mapstructure:"idempotent"`
+}
+
+type Dial struct {
+	ConnectTimeout time.Duration `env:"CONNECT_TIMEOUT" mapstructure:"connect_timeout"`
+	MaxRecvMsgSize int           `env:"MAX_RECV_MSG_SIZE" mapstructure:"max_recv_msg_size"`
+	MaxSendMsgSize int           `env:"MAX_SEND_MSG_SIZE" mapstructure:"max_send_msg_size"`
+	TLS            UpstreamTLS   `envPrefix:"TLS_" mapstructure:"tls"`
+}
+
+// UpstreamTLS verifies the upstream against CAFile under ServerName (SNI).
+type UpstreamTLS struct {
+	Enabled            bool   `env:"ENABLED" mapstructure:"enabled"`
+	CAFile             string `env:"CA_FILE" mapstructure:"ca_file"`
+	ServerName         string `env:"SERVER_NAME" mapstructure:"server_name"`
+	CertFile           string `env:"CERT_FILE" mapstructure:"cert_file"`
+	KeyFile            string `env:"KEY_FILE" mapstructure:"key_file"`
+	MinVersion         string `env:"MIN_VERSION" mapstructure:"min_version"`
+	InsecureSkipVerify bool   `env:"INSECURE_SKIP_VERIFY" mapstructure:"insecure_skip_verify"`
+}
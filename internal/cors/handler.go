@@ -0,0 +1,79 @@
+package cors
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Handler applies the CORS policy in front of next.
+type Handler struct {
+	next   http.Handler
+	policy atomic.Pointer[Policy]
+}
+
+func NewHandler(policy *Policy, next http.Handler) *Handler {
+	h := &Handler{next: next}
+	h.policy.Store(policy)
+
+	return h
+}
+
+func (h *Handler) SetPolicy(policy *Policy) {
+	h.policy.Store(policy)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+
+	rt := h.policy.Load().match(r.URL.Path)
+	allowed := rt != nil && rt.allowOrigin(origin)
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+
+		requested := r.Header.Get("Access-Control-Request-Headers")
+
+		// A refused preflight gets no CORS headers.
+		if allowed && rt.allowMethod(r.Header.Get("Access-Control-Request-Method")) && rt.allowHeaders(requested) {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Methods", rt.allowMethods)
+
+			if requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+
+			if rt.credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if rt.maxAge != "" {
+				header.Set("Access-Control-Max-Age", rt.maxAge)
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if allowed {
+		header.Set("Access-Control-Allow-Origin", origin)
+
+		if rt.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if rt.exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", rt.exposeHeaders)
+		}
+	}
+
+	h.next.ServeHTTP(w, r)
+}
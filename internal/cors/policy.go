@@ -0,0 +1,239 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+type Policy struct {
+	routes []*route
+}
+
+type route struct {
+	prefix        string
+	origins       []origin
+	anyOrigin     bool
+	methods       map[string]struct{}
+	allowMethods  string
+	headers       map[string]struct{}
+	anyHeader     bool
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+type origin struct {
+	prefix   string
+	suffix   string
+	wildcard bool
+}
+
+func NewPolicy(cfg *config.CORS) (*Policy, error) {
+	base := config.CORSRoute{
+		Prefix:           "/",
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: &cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+
+	p := &Policy{}
+	seen := make(map[string]struct{})
+
+	for _, rc := range append(cfg.Routes, base) {
+		if !strings.HasPrefix(rc.Prefix, "/") {
+			return nil, fmt.Errorf("cors route prefix %q must start with a slash", rc.Prefix)
+		}
+
+		if _, ok := seen[rc.Prefix]; ok {
+			if rc.Prefix == "/" {
+				// An explicit "/" route takes precedence over the top level.
+				continue
+			}
+
+			return nil, fmt.Errorf("duplicate cors route prefix %q", rc.Prefix)
+		}
+
+		seen[rc.Prefix] = struct{}{}
+
+		r, err := newRoute(inherit(rc, base))
+		if err != nil {
+			return nil, fmt.Errorf("cors route %s: %w", rc.Prefix, err)
+		}
+
+		p.routes = append(p.routes, r)
+	}
+
+	sort.SliceStable(p.routes, func(i, j int) bool {
+		return len(p.routes[i].prefix) > len(p.routes[j].prefix)
+	})
+
+	return p, nil
+}
+
+func inherit(rc, base config.CORSRoute) config.CORSRoute {
+	if len(rc.AllowedOrigins) == 0 {
+		rc.AllowedOrigins = base.AllowedOrigins
+	}
+
+	if len(rc.AllowedMethods) == 0 {
+		rc.AllowedMethods = base.AllowedMethods
+	}
+
+	if len(rc.AllowedHeaders) == 0 {
+		rc.AllowedHeaders = base.AllowedHeaders
+	}
+
+	if len(rc.ExposedHeaders) == 0 {
+		rc.ExposedHeaders = base.ExposedHeaders
+	}
+
+	if rc.AllowCredentials == nil {
+		rc.AllowCredentials = base.AllowCredentials
+	}
+
+	if rc.MaxAge == 0 {
+		rc.MaxAge = base.MaxAge
+	}
+
+	return rc
+}
+
+func newRoute(rc config.CORSRoute) (*route, error) {
+	r := &route{
+		prefix:  rc.Prefix,
+		methods: make(map[string]struct{}),
+		headers: make(map[string]struct{}),
+	}
+
+	for _, o := range rc.AllowedOrigins {
+		o = strings.ToLower(strings.TrimSpace(o))
+
+		switch {
+		case o == "":
+			continue
+		case o == "*":
+			r.anyOrigin = true
+		case strings.Count(o, "*") > 1:
+			return nil, fmt.Errorf("origin %q has more than one wildcard", o)
+		default:
+			prefix, suffix, wildcard := strings.Cut(o, "*")
+			r.origins = append(r.origins, origin{prefix: prefix, suffix: suffix, wildcard: wildcard})
+		}
+	}
+
+	methods := make([]string, 0, len(rc.AllowedMethods))
+	for _, m := range rc.AllowedMethods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m == "" {
+			continue
+		}
+
+		r.methods[m] = struct{}{}
+		methods = append(methods, m)
+	}
+
+	r.allowMethods = strings.Join(methods, ", ")
+
+	for _, h := range rc.AllowedHeaders {
+		h = strings.TrimSpace(h)
+
+		switch h {
+		case "":
+		case "*":
+			r.anyHeader = true
+		default:
+			r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+		}
+	}
+
+	exposed := make([]string, 0, len(rc.ExposedHeaders))
+	for _, h := range rc.ExposedHeaders {
+		if h = strings.TrimSpace(h); h != "" {
+			exposed = append(exposed, http.CanonicalHeaderKey(h))
+		}
+	}
+
+	r.exposeHeaders = strings.Join(exposed, ", ")
+	r.credentials = rc.AllowCredentials != nil && *rc.AllowCredentials
+
+	// Echoing any origin with credentials would let every site make credentialed calls.
+	if r.anyOrigin && r.credentials {
+		return nil, fmt.Errorf("allowed origin \"*\" cannot be combined with allow credentials")
+	}
+
+	if rc.MaxAge < 0 {
+		return nil, fmt.Errorf("max age must not be negative")
+	}
+
+	if rc.MaxAge > 0 {
+		r.maxAge = strconv.Itoa(int(rc.MaxAge.Seconds()))
+	}
+
+	return r, nil
+}
+
+func (p *Policy) match(path string) *route {
+	for _, r := range p.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (r *route) allowOrigin(o string) bool {
+	if r.anyOrigin {
+		return true
+	}
+
+	o = strings.ToLower(o)
+
+	for _, allowed := range r.origins {
+		if !allowed.wildcard {
+			if o == allowed.prefix {
+				return true
+			}
+
+			continue
+		}
+
+		if len(o) > len(allowed.prefix)+len(allowed.suffix) &&
+			strings.HasPrefix(o, allowed.prefix) && strings.HasSuffix(o, allowed.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *route) allowMethod(m string) bool {
+	_, ok := r.methods[strings.ToUpper(m)]
+	return ok
+}
+
+func (r *route) allowHeaders(requested string) bool {
+	if r.anyHeader {
+		return true
+	}
+
+	for _, h := range strings.Split(requested, ",") {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+
+		if _, ok := r.headers[http.CanonicalHeaderKey(h)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+var DefaultRules = []config.TimeoutRule{
+	{
+		Methods: []string{"/" + usersv1.UsersAuthService_ServiceDesc.ServiceName + "/"},
+		Timeout: 5 * time.Second,
+	},
+	{
+		Methods: []string{questionsv1.QuestionsService_GetQuestionBatch_FullMethodName},
+		Timeout: 10 * time.Second,
+	},
+}
+
+type prefix struct {
+	prefix  string
+	timeout time.Duration
+}
+
+// Policy resolves the timeout of a call.
+type Policy struct {
+	fallback time.Duration
+	methods  map[string]time.Duration
+	prefixes []prefix
+}
+
+func NewPolicy(cfg *config.Timeouts) (*Policy, error) {
+	if cfg.Default < 0 {
+		return nil, fmt.Errorf("default timeout must not be negative")
+	}
+
+	p := &Policy{
+		fallback: cfg.Default,
+		methods:  make(map[string]time.Duration),
+	}
+
+	rules := cfg.Rules
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+
+	for _, rule := range rules {
+		if rule.Timeout <= 0 {
+			return nil, fmt.Errorf("timeout rule %v: timeout must be positive", rule.Methods)
+		}
+
+		for _, m := range rule.Methods {
+			if strings.HasSuffix(m, "/") {
+				p.prefixes = append(p.prefixes, prefix{prefix: m, timeout: rule.Timeout})
+			} else {
+				p.methods[m] = rule.Timeout
+			}
+		}
+	}
+
+	sort.SliceStable(p.prefixes, func(i, j int) bool {
+		return len(p.prefixes[i].prefix) > len(p.prefixes[j].prefix)
+	})
+
+	return p, nil
+}
+
+func (p *Policy) Timeout(fullMethod string) time.Duration {
+	if timeout, ok := p.methods[fullMethod]; ok {
+		return timeout
+	}
+
+	for _, pr := range p.prefixes {
+		if strings.HasPrefix(fullMethod, pr.prefix) {
+			return pr.timeout
+		}
+	}
+
+	return p.fallback
+}
+
+// Enforcer applies the current policy to outgoing calls.
+type Enforcer struct {
+	policy atomic.Pointer[Policy]
+}
+
+func NewEnforcer(policy *Policy) *Enforcer {
+	e := &Enforcer{}
+	e.policy.Store(policy)
+
+	return e
+}
+
+func (e *Enforcer) SetPolicy(policy *Policy) {
+	e.policy.Store(policy)
+}
+
+func (e *Enforcer) WithTimeout(ctx context.Context, fullMethod string) (context.Context, context.CancelFunc) {
+	timeout := e.policy.Load().Timeout(fullMethod)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// CallerImposed reports a caller deadline shorter than the policy timeout.
+func (e *Enforcer) CallerImposed(ctx context.Context, fullMethod string) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+
+	timeout := e.policy.Load().Timeout(fullMethod)
+
+	return timeout <= 0 || time.Until(deadline) < timeout
+}
+
+func (e *Enforcer) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := e.WithTimeout(ctx, method)
+		defer cancel()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if _, ok := status.FromError(err); !ok {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,82 @@
+package drain
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+var cutCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_drain_cut_calls_total",
+		Help: "Total number of in-flight calls cut off when the drain timeout expired",
+	},
+	[]string{"protocol"},
+)
+
+func init() {
+	prometheus.MustRegister(cutCounter)
+}
+
+type Tracker struct {
+	http atomic.Int64
+	grpc atomic.Int64
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+func (t *Tracker) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.http.Add(1)
+		defer t.http.Add(-1)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t *Tracker) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		t.grpc.Add(1)
+		defer t.grpc.Add(-1)
+
+		return handler(ctx, req)
+	}
+}
+
+func (t *Tracker) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t.grpc.Add(1)
+		defer t.grpc.Add(-1)
+
+		return handler(srv, ss)
+	}
+}
+
+// InFlight returns the number of calls in flight for protocol.
+func (t *Tracker) InFlight(protocol string) int64 {
+	if protocol == ProtocolGRPC {
+		return t.grpc.Load()
+	}
+
+	return t.http.Load()
+}
+
+// Cut records the calls of protocol still in flight as cut off and returns their number.
+func (t *Tracker) Cut(protocol string) int64 {
+	n := t.InFlight(protocol)
+	if n > 0 {
+		cutCounter.WithLabelValues(protocol).Add(float64(n))
+	}
+
+	return n
+}
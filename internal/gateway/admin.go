@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/breaker"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/lb"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/ratelimit"
+)
+
+//go:embed admin.html
+var adminPage string
+
+var adminTemplate = template.Must(template.New("upstreams").Parse(adminPage))
+
+// UpstreamStatus is everything the gateway believes about an upstream.
+type UpstreamStatus struct {
+	Name     string            `json:"name"`
+	Target   string            `json:"target"`
+	State    string            `json:"state"`
+	Services []string          `json:"services"`
+	Resolver ResolverState     `json:"resolver"`
+	Consul   *PlanStatus       `json:"consul,omitempty"`
+	SubConns []lb.SubConnState `json:"subconns"`
+	Breakers []breaker.Status  `json:"breakers,omitempty"`
+}
+
+type AdminStatus struct {
+	Upstreams []UpstreamStatus  `json:"upstreams"`
+	RateLimit *ratelimit.Status `json:"rate_limit,omitempty"`
+}
+
+func (gt *Gateway) AdminStatus() AdminStatus {
+	var status AdminStatus
+
+	plans := make(map[string]*Plan, len(gt.plans))
+	for _, plan := range gt.plans {
+		plans[plan.service] = plan
+	}
+
+	breakers := make(map[string][]breaker.Status)
+	if gt.breakers != nil {
+		for _, st := range gt.breakers.Snapshot() {
+			breakers[st.Upstream] = append(breakers[st.Upstream], st)
+		}
+	}
+
+	for name, conn := range gt.grpcConns {
+		upstream := UpstreamStatus{
+			Name:     name,
+			Target:   conn.Target(),
+			State:    conn.GetState().String(),
+			Services: gt.routes.Services(name),
+			SubConns: lb.SubConns(name),
+			Breakers: breakers[name],
+		}
+
+		if builder, ok := gt.resolvers[name]; ok {
+			upstream.Resolver = builder.State()
+		}
+
+		if plan, ok := plans[name]; ok {
+			st := plan.Status()
+			upstream.Consul = &st
+		}
+
+		status.Upstreams = append(status.Upstreams, upstream)
+	}
+
+	sort.Slice(status.Upstreams, func(i, j int) bool {
+		return status.Upstreams[i].Name < status.Upstreams[j].Name
+	})
+
+	if gt.limiter != nil {
+		st := gt.limiter.Snapshot()
+		status.RateLimit = &st
+	}
+
+	return status
+}
+
+// upstreamsHandler serves AdminStatus as JSON, or as a page for browsers.
+func (gt *Gateway) upstreamsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		status := gt.AdminStatus()
+
+		if r.URL.Query().Get("format") == "html" || strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = adminTemplate.Execute(w, status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
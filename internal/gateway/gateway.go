@@ -3,26 +3,50 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"github.com/QuizWars-Ecosystem/go-common/pkg/grpcx/telemetry"
-	"github.com/QuizWars-Ecosystem/go-common/pkg/log"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/accesslog"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/breaker"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/bridge"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/cors"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/deadline"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/drain"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/health"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/lockout"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/logging"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/metrics"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/openapi"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/policy"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/ratelimit"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/redact"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/rest"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/telemetry"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/tlsconfig"
 	grpcrecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hashicorp/consul/api"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/siderolabs/grpc-proxy/proxy"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"net/http"
-	"strings"
+	"sync"
 )
 
 type ServiceOption struct {
 	Address      string
-	RegisterFunc []func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+	Services     []string
+	Optional     bool
+	RegisterFunc []registry.RegisterFunc
 	DialOptions  []grpc.DialOption
 }
 
@@ -31,47 +55,217 @@ type Gateway struct {
 	cancel       context.CancelFunc
 	consul       *api.Client
 	serveMux     *http.ServeMux
+	handler      http.Handler
+	cors         *cors.Handler
 	grpcProxyMux *grpc.Server
+	handlerProxy *grpc.Server
 	plans        []*Plan
 	plansInputs  []chan []*api.ServiceEntry
 	plansErrCh   chan error
 	grpcConns    map[string]*grpc.ClientConn
-	logger       *log.Logger
+	resolvers    map[string]*Builder
+	routes       *Routes
+	httpServices map[string]struct{}
+	bindings     []rest.Binding
+	logger       *logging.Logger
 	provider     *trace.TracerProvider
+	auth         *auth.Authenticator
+	binder       *policy.Binder
+	rbac         *policy.RBAC
+	limiter      *ratelimit.Limiter
+	lockout      *lockout.Guard
+	deadlines    *deadline.Enforcer
+	breakers     *breaker.Set
+	health       *health.Checker
+	tracker      *drain.Tracker
+	accessLog    *accesslog.Logger
+	redactor     *redact.Redactor
+	metrics      *metrics.HTTP
+	optional     map[string]bool
+
+	healthMx       sync.Mutex
+	healthServer   *grpchealth.Server
+	healthServices map[string]healthpb.HealthCheckResponse_ServingStatus
 }
 
-func NewGateway(consulURL string, serviceOpts []*ServiceOption, logger *log.Logger) (*Gateway, error) {
+func NewGateway(cfg *config.Config, serviceOpts []*ServiceOption, logger *logging.Logger) (*Gateway, error) {
 	var gt Gateway
 
 	z := logger.Zap()
 
-	runtimeMux := runtime.NewServeMux(standardServerMuxOptions(z)...)
+	gt.ctx, gt.cancel = context.WithCancel(context.Background())
+
+	if cfg.Auth.Enabled {
+		verifier := auth.NewVerifier(gt.ctx, &cfg.Auth, z)
+		gt.auth = auth.NewAuthenticator(verifier, auth.NewPolicy(cfg.Auth.PublicMethods), z)
+	}
+
+	gt.binder = policy.NewBinder(policy.DefaultBindings, z)
+	gt.rbac = policy.NewRBAC(policy.DefaultRoles, z)
+
+	if cfg.RateLimit.Enabled {
+		limiter, err := ratelimit.New(gt.ctx, &cfg.RateLimit, z)
+		if err != nil {
+			gt.cancel()
+			logger.Zap().Error("error initializing rate limiter", zap.Error(err))
+			return nil, fmt.Errorf("error initializing rate limiter: %w", err)
+		}
+
+		gt.limiter = limiter
+	}
+
+	if cfg.Lockout.Enabled {
+		guard, err := lockout.NewGuard(&cfg.Lockout, z)
+		if err != nil {
+			gt.cancel()
+			logger.Zap().Error("error initializing login lockout", zap.Error(err))
+			return nil, fmt.Errorf("error initializing login lockout: %w", err)
+		}
+
+		gt.lockout = guard
+	}
+
+	timeouts, err := deadline.NewPolicy(&cfg.Timeouts)
+	if err != nil {
+		gt.cancel()
+		logger.Zap().Error("error initializing timeout policy", zap.Error(err))
+		return nil, fmt.Errorf("error initializing timeout policy: %w", err)
+	}
+
+	gt.deadlines = deadline.NewEnforcer(timeouts)
+
+	if cfg.Breaker.Enabled {
+		breakers, err := breaker.NewSet(&cfg.Breaker, z)
+		if err != nil {
+			gt.cancel()
+			logger.Zap().Error("error initializing circuit breakers", zap.Error(err))
+			return nil, fmt.Errorf("error initializing circuit breakers: %w", err)
+		}
+
+		gt.breakers = breakers
+	}
+
+	gt.redactor = redact.New(&cfg.Redaction)
+
+	if cfg.AccessLog.Enabled {
+		gt.accessLog = accesslog.New(&cfg.AccessLog, cfg.TrustForwardedFor, gt.redactor, z)
+	}
+
+	provider, err := telemetry.NewTracerProvider(gt.ctx, &cfg.Tracing, gt.redactor)
+	if err != nil {
+		gt.cancel()
+		logger.Zap().Error("error initializing telemetry tracer", zap.Error(err))
+		return nil, fmt.Errorf("error initializing telemetry tracer: %w", err)
+	}
+
+	gt.provider = provider
+
+	gt.metrics, err = metrics.New(&cfg.Metrics, func(fullMethodName string) (string, bool) {
+		return gt.routes.Upstream(fullMethodName)
+	})
+	if err != nil {
+		gt.cancel()
+		logger.Zap().Error("error initializing metrics", zap.Error(err))
+		return nil, fmt.Errorf("error initializing metrics: %w", err)
+	}
+
+	errHandler := standardErrorHandler(z)
+
+	middlewares := []runtime.Middleware{telemetry.Middleware(), gt.metrics.Middleware()}
+
+	if gt.auth != nil {
+		middlewares = append(middlewares, auth.NewMiddleware(gt.auth, errHandler))
+	}
+
+	if gt.limiter != nil {
+		middlewares = append(middlewares, ratelimit.NewMiddleware(gt.limiter, cfg.TrustForwardedFor, errHandler))
+	}
+
+	if gt.lockout != nil {
+		middlewares = append(middlewares, lockout.NewMiddleware(gt.lockout, cfg.TrustForwardedFor, errHandler))
+	}
+
+	if gt.accessLog != nil {
+		middlewares = append(middlewares, gt.accessLog.Middleware())
+	}
+
+	runtimeMux := runtime.NewServeMux(standardServerMuxOptions(z, errHandler, middlewares...)...)
 
 	serveMux := http.NewServeMux()
-	serveMux.Handle("/", runtimeMux)
-	serveMux.Handle("/metrics", promhttp.Handler())
+	serveMux.Handle("/", gt.metrics.Handler(runtimeMux))
+	serveMux.Handle("/metrics", metrics.Handler())
 
-	cfg := api.DefaultConfig()
-	cfg.Address = consulURL
+	if gt.lockout != nil {
+		serveMux.Handle("/admin/lockouts", policy.RequireRole(gt.auth, usersv1.Role_ROLE_ADMIN, errHandler, gt.lockout.AdminHandler()))
+	}
+
+	if gt.breakers != nil {
+		serveMux.Handle("/admin/breakers", policy.RequireRole(gt.auth, usersv1.Role_ROLE_ADMIN, errHandler, gt.breakers.AdminHandler()))
+	}
 
-	client, err := api.NewClient(cfg)
+	serveMux.Handle("/admin/upstreams", policy.RequireRole(gt.auth, usersv1.Role_ROLE_ADMIN, errHandler, gt.upstreamsHandler()))
+
+	consulCfg := api.DefaultConfig()
+	consulCfg.Address = cfg.ConsulURL
+
+	client, err := api.NewClient(consulCfg)
 	if err != nil {
 		logger.Zap().Fatal("error creating client client", zap.Error(err))
 		return nil, fmt.Errorf("error creating client client: %w", err)
 	}
 
 	gt.serveMux = serveMux
-	gt.ctx, gt.cancel = context.WithCancel(context.Background())
 	gt.consul = client
 	gt.logger = logger
 	gt.grpcConns = make(map[string]*grpc.ClientConn)
+	gt.resolvers = make(map[string]*Builder)
+	gt.routes = NewRoutes(gt.grpcConns)
+	gt.httpServices = make(map[string]struct{})
+	gt.health = health.NewChecker()
+	gt.tracker = drain.NewTracker()
+	gt.optional = make(map[string]bool)
+
+	routes := make(map[string]string)
 
 	var conn *grpc.ClientConn
 	for _, opt := range serviceOpts {
 		queue := make(chan []*api.ServiceEntry)
 
-		dialOpts := []grpc.DialOption{grpc.WithResolvers(NewBuilder(queue, z))}
+		builder := NewBuilder(queue, z)
+		gt.resolvers[opt.Address] = builder
+
+		dialOpts := []grpc.DialOption{grpc.WithResolvers(builder)}
 		dialOpts = append(dialOpts, standardDialOptions(z)...)
+		unaryInterceptors := []grpc.UnaryClientInterceptor{grpcprometheus.UnaryClientInterceptor}
+
+		if gt.lockout != nil {
+			unaryInterceptors = append(unaryInterceptors, gt.lockout.UnaryClientInterceptor())
+		}
+
+		unaryInterceptors = append(unaryInterceptors,
+			gt.rbac.UnaryClientInterceptor(),
+			gt.binder.UnaryClientInterceptor(),
+		)
+
+		if gt.breakers != nil {
+			unaryInterceptors = append(unaryInterceptors, gt.breakers.UnaryClientInterceptor(opt.Address, gt.deadlines))
+		}
+
+		unaryInterceptors = append(unaryInterceptors, gt.deadlines.UnaryClientInterceptor())
+
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(unaryInterceptors...))
+		dialOpts = append(dialOpts, grpc.WithChainStreamInterceptor(grpcprometheus.StreamClientInterceptor))
+
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(
+			otelgrpc.NewClientHandler(
+				otelgrpc.WithTracerProvider(gt.provider),
+			),
+		))
+
+		if gt.accessLog != nil {
+			dialOpts = append(dialOpts, grpc.WithStatsHandler(gt.accessLog.ClientHandler(opt.Address)))
+		}
+
 		dialOpts = append(dialOpts, opt.DialOptions...)
 
 		conn, err = grpc.NewClient(fmt.Sprintf(customScheme+":///%s", opt.Address), dialOpts...)
@@ -80,31 +274,95 @@ func NewGateway(consulURL string, serviceOpts []*ServiceOption, logger *log.Logg
 			return nil, fmt.Errorf("error creating grpc client: %w", err)
 		}
 
-		gt.grpcConns[strings.ReplaceAll(opt.Address, "-", "")] = conn
+		gt.grpcConns[opt.Address] = conn
+		gt.optional[opt.Address] = opt.Optional
+
+		for _, service := range opt.Services {
+			routes[service] = opt.Address
+
+			for _, name := range registry.Expand(service) {
+				gt.httpServices[name] = struct{}{}
+			}
+		}
 
 		for _, registerFunc := range opt.RegisterFunc {
-			if err = registerFunc(gt.ctx, runtimeMux, conn); err != nil {
+			if err = registerFunc(gt.ctx, runtimeMux, gt.routes); err != nil {
 				logger.Zap().Fatal("error registering service", zap.String("address", opt.Address), zap.Error(err))
 				return nil, fmt.Errorf("error registering service: %w", err)
 			}
 		}
 
-		logger.Zap().Info("registered service", zap.String("address", opt.Address))
+		logger.Zap().Info("registered service", zap.String("address", opt.Address), zap.Strings("services", opt.Services))
 
 		plan := NewPlan(client, logger, opt.Address, queue)
 
+		gt.health.Add(planCheck(plan, opt.Optional), connCheck(opt.Address, conn, opt.Optional))
+
 		gt.plans = append(gt.plans, plan)
 		gt.plansInputs = append(gt.plansInputs, queue)
 	}
 
-	p := NewProxy(gt.grpcConns, logger.Zap())
+	gt.routes.Update(routes)
 
-	provider, err := telemetry.NewTracerProvider(gt.ctx, "gateway", "otel-collector:4317")
+	bindings, err := rest.LoadRules(cfg.HTTPRulesPath)
 	if err != nil {
-		logger.Zap().Error("error initializing telemetry tracer", zap.Error(err))
+		gt.cancel()
+		logger.Zap().Error("error loading http rules", zap.Error(err))
+		return nil, fmt.Errorf("error loading http rules: %w", err)
 	}
 
-	gt.provider = provider
+	for _, binding := range bindings {
+		if _, ok := gt.httpServices[serviceName(binding.FullMethod)]; !ok {
+			continue
+		}
+
+		if err = rest.Handle(runtimeMux, gt.routes, binding); err != nil {
+			gt.cancel()
+			logger.Zap().Error("error registering http rule", zap.Error(err))
+			return nil, fmt.Errorf("error registering http rule: %w", err)
+		}
+
+		gt.bindings = append(gt.bindings, binding)
+	}
+
+	serveMux.Handle("/openapi.json", openapi.Handler(gt.openAPIDocument))
+
+	if cfg.Local {
+		serveMux.Handle("/swagger/", openapi.SwaggerHandler())
+	}
+
+	p := NewProxy(gt.routes, gt.deadlines, logger.Zap())
+
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpcrecovery.StreamServerInterceptor(),
+		grpcprometheus.StreamServerInterceptor,
+		requestid.StreamServerInterceptor(),
+	}
+
+	if gt.auth != nil {
+		streamInterceptors = append(streamInterceptors, gt.auth.StreamServerInterceptor())
+	}
+
+	if gt.accessLog != nil {
+		streamInterceptors = append(streamInterceptors, gt.accessLog.StreamServerInterceptor())
+	}
+
+	if gt.limiter != nil {
+		streamInterceptors = append(streamInterceptors, gt.limiter.StreamServerInterceptor(cfg.TrustForwardedFor))
+	}
+
+	streamInterceptors = append(streamInterceptors,
+		gt.rbac.StreamServerInterceptor(),
+		gt.binder.StreamServerInterceptor(),
+	)
+
+	if gt.lockout != nil {
+		streamInterceptors = append(streamInterceptors, gt.lockout.StreamServerInterceptor(cfg.TrustForwardedFor))
+	}
+
+	if gt.breakers != nil {
+		streamInterceptors = append(streamInterceptors, gt.breakers.StreamServerInterceptor(gt.routes, gt.deadlines))
+	}
 
 	grpcServerOpts := []grpc.ServerOption{
 		grpc.ForceServerCodecV2(proxy.Codec()),
@@ -112,24 +370,76 @@ func NewGateway(consulURL string, serviceOpts []*ServiceOption, logger *log.Logg
 		grpc.ChainUnaryInterceptor(
 			grpcrecovery.UnaryServerInterceptor(),
 			grpcprometheus.UnaryServerInterceptor,
+			requestid.UnaryServerInterceptor(),
 		),
-		grpc.ChainStreamInterceptor(
-			grpcrecovery.StreamServerInterceptor(),
-			grpcprometheus.StreamServerInterceptor,
-		),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 		grpc.StatsHandler(
 			otelgrpc.NewServerHandler(
-				otelgrpc.WithTracerProvider(provider),
+				otelgrpc.WithTracerProvider(gt.provider),
 			),
 		),
 	}
 
+	if gt.accessLog != nil {
+		grpcServerOpts = append(grpcServerOpts, grpc.StatsHandler(gt.accessLog.ServerHandler()))
+	}
+
 	grpcServerOpts = append(grpcServerOpts, standardServerOptions(logger.Zap())...)
 
-	grpcProxy := grpc.NewServer(grpcServerOpts...)
+	// Calls over HTTP are tracked by the HTTP handler already.
+	proxyOpts := append(grpcServerOpts[:len(grpcServerOpts):len(grpcServerOpts)],
+		grpc.ChainUnaryInterceptor(gt.tracker.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(gt.tracker.StreamServerInterceptor()),
+	)
+
+	if cfg.GRPCTLS.Enabled {
+		tlsCfg, err := tlsconfig.NewServerConfig(&cfg.GRPCTLS)
+		if err != nil {
+			gt.cancel()
+			logger.Zap().Error("error initializing grpc tls", zap.Error(err))
+			return nil, fmt.Errorf("error initializing grpc tls: %w", err)
+		}
+
+		proxyOpts = append(proxyOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	grpcProxy := grpc.NewServer(proxyOpts...)
 
 	gt.grpcProxyMux = grpcProxy
 
+	// GracefulStop does not support handler based transports, so bridged calls
+	// get a server of their own.
+	gt.handlerProxy = grpc.NewServer(grpcServerOpts...)
+	gt.handler = bridge.NewHandler(gt.handlerProxy, serveMux)
+
+	// Registered services win over the unknown service handler.
+	gt.healthServer = grpchealth.NewServer()
+	healthpb.RegisterHealthServer(gt.grpcProxyMux, gt.healthServer)
+	healthpb.RegisterHealthServer(gt.handlerProxy, gt.healthServer)
+	gt.updateHealth()
+
+	if cfg.CORS.Enabled {
+		corsPolicy, err := cors.NewPolicy(&cfg.CORS)
+		if err != nil {
+			gt.cancel()
+			logger.Zap().Error("error initializing cors policy", zap.Error(err))
+			return nil, fmt.Errorf("error initializing cors policy: %w", err)
+		}
+
+		gt.cors = cors.NewHandler(corsPolicy, gt.handler)
+		gt.handler = gt.cors
+	}
+
+	if gt.accessLog != nil {
+		gt.handler = gt.accessLog.Handler(gt.handler)
+	}
+
+	gt.handler = requestid.Handler(gt.handler)
+
+	gt.handler = telemetry.Handler(gt.handler, gt.provider)
+
+	gt.handler = gt.tracker.Handler(gt.handler)
+
 	return &gt, err
 }
 
@@ -137,10 +447,33 @@ func (gt *Gateway) ServeMux() *http.ServeMux {
 	return gt.serveMux
 }
 
+func (gt *Gateway) Handler() http.Handler {
+	return gt.handler
+}
+
+func (gt *Gateway) Health() *health.Checker {
+	return gt.health
+}
+
+func (gt *Gateway) Tracker() *drain.Tracker {
+	return gt.tracker
+}
+
+func (gt *Gateway) Drain() {
+	gt.health.SetDraining()
+	gt.healthServer.Shutdown()
+
+	gt.logger.Zap().Info("gateway draining")
+}
+
 func (gt *Gateway) Proxy() *grpc.Server {
 	return gt.grpcProxyMux
 }
 
+func (gt *Gateway) HandlerProxy() *grpc.Server {
+	return gt.handlerProxy
+}
+
 func (gt *Gateway) Start() error {
 	errCh := make(chan error, 10)
 
@@ -148,6 +481,20 @@ func (gt *Gateway) Start() error {
 		plan.Run(errCh)
 	}
 
+	// Dial right away instead of on the first call, readiness waits for it.
+	for _, conn := range gt.grpcConns {
+		conn.Connect()
+		go gt.watchConn(conn)
+	}
+
+	if gt.auth != nil {
+		gt.auth.Run(gt.ctx)
+	}
+
+	if gt.lockout != nil {
+		gt.lockout.Run(gt.ctx)
+	}
+
 	gt.plansErrCh = errCh
 	go gt.handleWatchErrors()
 
@@ -163,6 +510,8 @@ func (gt *Gateway) Stop() error {
 
 	close(gt.plansErrCh)
 
+	gt.handlerProxy.Stop()
+
 	var errs error
 	var err error
 
@@ -173,6 +522,13 @@ func (gt *Gateway) Stop() error {
 		}
 	}
 
+	if gt.limiter != nil {
+		if err = gt.limiter.Close(); err != nil {
+			gt.logger.Zap().Error("error closing rate limiter", zap.Error(err))
+			errs = multierr.Append(errs, err)
+		}
+	}
+
 	if err = gt.provider.Shutdown(gt.ctx); err != nil {
 		gt.logger.Zap().Error("error shutting down tracer", zap.Error(err))
 	}
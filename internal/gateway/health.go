@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/health"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+// planCheck passes once the Consul watch of the upstream delivered a result.
+func planCheck(plan *Plan, optional bool) health.Check {
+	return health.Check{
+		Name:     "consul:" + plan.service,
+		Optional: optional,
+		Func: func() error {
+			if plan.Status().UpdatedAt.IsZero() {
+				return fmt.Errorf("no service entries received yet")
+			}
+
+			return nil
+		},
+	}
+}
+
+// connCheck passes while the connection to the upstream is READY.
+func connCheck(name string, conn *grpc.ClientConn, optional bool) health.Check {
+	return health.Check{
+		Name:     "upstream:" + name,
+		Optional: optional,
+		Func: func() error {
+			state := conn.GetState()
+
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Idle:
+				conn.Connect()
+			}
+
+			return fmt.Errorf("connection is %s", state)
+		},
+	}
+}
+
+func (gt *Gateway) watchConn(conn *grpc.ClientConn) {
+	state := conn.GetState()
+
+	for {
+		gt.updateHealth()
+
+		if !conn.WaitForStateChange(gt.ctx, state) {
+			return
+		}
+
+		state = conn.GetState()
+	}
+}
+
+func (gt *Gateway) updateHealth() {
+	gt.healthMx.Lock()
+	defer gt.healthMx.Unlock()
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	if gt.health.Draining() {
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	services := make(map[string]healthpb.HealthCheckResponse_ServingStatus)
+
+	for name, conn := range gt.grpcConns {
+		status := healthpb.HealthCheckResponse_SERVING
+		if conn.GetState() != connectivity.Ready {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+
+			if !gt.optional[name] {
+				overall = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		for _, entry := range gt.routes.Services(name) {
+			for _, service := range registry.Expand(entry) {
+				services[service] = status
+			}
+		}
+	}
+
+	for service := range gt.healthServices {
+		if _, ok := services[service]; !ok {
+			gt.healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVICE_UNKNOWN)
+		}
+	}
+
+	for service, status := range services {
+		gt.healthServer.SetServingStatus(service, status)
+	}
+
+	gt.healthServer.SetServingStatus("", overall)
+	gt.healthServices = services
+}
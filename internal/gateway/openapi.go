@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"sort"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/openapi"
+)
+
+const apiTitle = "QuizWars API Gateway"
+
+// openAPIDocument describes the routes registered on the runtime mux.
+func (gt *Gateway) openAPIDocument() (*openapi.Document, error) {
+	isPublic := func(string) bool { return true }
+	if gt.auth != nil {
+		isPublic = gt.auth.IsPublic
+	}
+
+	b := openapi.NewBuilder(apiTitle, "v1", isPublic)
+
+	services := make([]string, 0, len(gt.httpServices))
+	for service := range gt.httpServices {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		if err := b.AddService(service); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, binding := range gt.bindings {
+		if err := b.AddBinding(binding); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Document(), nil
+}
@@ -1,9 +1,14 @@
 package gateway
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"time"
 
-	"github.com/QuizWars-Ecosystem/api-gateway/internal/middlewares"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/lb"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
 	"github.com/QuizWars-Ecosystem/go-common/pkg/grpcx/errors"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/siderolabs/grpc-proxy/proxy"
@@ -33,14 +38,24 @@ func standardDialOptions(_ *zap.Logger) []grpc.DialOption {
 		grpc.WithMaxCallAttempts(maxCallAttempts),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(grpc.ForceCodecV2(proxy.Codec())),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingPolicy":%q}`, lb.Name)),
 	}
 }
 
-func standardServerMuxOptions(logger *zap.Logger) []runtime.ServeMuxOption {
+// standardErrorHandler logs with the request ID of the failed request.
+func standardErrorHandler(logger *zap.Logger) runtime.ErrorHandlerFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		errors.NewCustomErrorHandler(requestid.Logger(r.Context(), logger))(ctx, mux, marshaler, w, r, err)
+	}
+}
+
+func standardServerMuxOptions(_ *zap.Logger, errHandler runtime.ErrorHandlerFunc, mws ...runtime.Middleware) []runtime.ServeMuxOption {
 	return []runtime.ServeMuxOption{
-		runtime.WithErrorHandler(errors.NewCustomErrorHandler(logger)),
-		runtime.WithMiddlewares(middlewares.NewLoggingMiddleware(logger)),
+		runtime.WithErrorHandler(errHandler),
+		runtime.WithIncomingHeaderMatcher(auth.HeaderMatcher),
+		runtime.WithMetadata(auth.Metadata),
+		runtime.WithMetadata(requestid.Metadata),
+		runtime.WithMiddlewares(mws...),
 	}
 }
 
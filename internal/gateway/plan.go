@@ -1,7 +1,10 @@
 package gateway
 
 import (
-	"github.com/QuizWars-Ecosystem/go-common/pkg/log"
+	"sync"
+	"time"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/logging"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/api/watch"
 )
@@ -10,14 +13,28 @@ const planType = "service"
 
 type Plan struct {
 	client  *api.Client
-	logger  *log.Logger
+	logger  *logging.Logger
 	service string
 	plan    *watch.Plan
 	input   chan<- []*api.ServiceEntry
 	errCh   chan<- error
+
+	mx        sync.Mutex
+	lastIndex uint64
+	entries   int
+	updatedAt time.Time
 }
 
-func NewPlan(client *api.Client, logger *log.Logger, serviceName string, input chan<- []*api.ServiceEntry) *Plan {
+// PlanStatus is what the Consul watch of an upstream last delivered.
+type PlanStatus struct {
+	Service   string    `json:"service"`
+	LastIndex uint64    `json:"last_index"`
+	Entries   int       `json:"entries"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	Stopped   bool      `json:"stopped"`
+}
+
+func NewPlan(client *api.Client, logger *logging.Logger, serviceName string, input chan<- []*api.ServiceEntry) *Plan {
 	p := &Plan{}
 
 	pl, _ := watch.Parse(map[string]interface{}{
@@ -37,9 +54,16 @@ func NewPlan(client *api.Client, logger *log.Logger, serviceName string, input c
 	return p
 }
 
-func (p *Plan) handle(_ uint64, data interface{}) {
+func (p *Plan) handle(idx uint64, data interface{}) {
 	if !p.plan.IsStopped() {
 		entries := data.([]*api.ServiceEntry)
+
+		p.mx.Lock()
+		p.lastIndex = idx
+		p.entries = len(entries)
+		p.updatedAt = time.Now()
+		p.mx.Unlock()
+
 		if len(entries) > 0 {
 			p.input <- entries
 		}
@@ -56,6 +80,19 @@ func (p *Plan) Run(errCh chan<- error) {
 	p.errCh = errCh
 }
 
+func (p *Plan) Status() PlanStatus {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return PlanStatus{
+		Service:   p.service,
+		LastIndex: p.lastIndex,
+		Entries:   p.entries,
+		UpdatedAt: p.updatedAt,
+		Stopped:   p.plan.IsStopped(),
+	}
+}
+
 func (p *Plan) Stop() {
 	p.plan.Stop()
 
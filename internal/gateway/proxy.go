@@ -3,32 +3,30 @@ package gateway
 import (
 	"context"
 	"errors"
-	"fmt"
-	"strings"
 
 	"github.com/siderolabs/grpc-proxy/proxy"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/deadline"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
 	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
 )
 
 var _ proxy.Backend = (*Proxy)(nil)
 
 type Proxy struct {
-	grpcConns map[string]*grpc.ClientConn
+	routes    *Routes
+	deadlines *deadline.Enforcer
 	logger    *zap.Logger
 }
 
-func NewProxy(grcConns map[string]*grpc.ClientConn, logger *zap.Logger) *Proxy {
-	connsMap := make(map[string]*grpc.ClientConn, len(grcConns))
-
-	for service, conn := range grcConns {
-		connsMap[fmt.Sprintf("/%s", service)] = conn
-	}
-
+func NewProxy(routes *Routes, deadlines *deadline.Enforcer, logger *zap.Logger) *Proxy {
 	return &Proxy{
-		grpcConns: connsMap,
+		routes:    routes,
+		deadlines: deadlines,
 		logger:    logger,
 	}
 }
@@ -38,15 +36,35 @@ func (p *Proxy) String() string {
 }
 
 func (p *Proxy) GetConnection(ctx context.Context, fullMethodName string) (context.Context, *grpc.ClientConn, error) {
-	name := strings.Split(fullMethodName, ".")[0]
+	if conn, ok := p.routes.Lookup(fullMethodName); ok {
+		ctx, cancel := p.deadlines.WithTimeout(outgoingContext(ctx), fullMethodName)
+
+		// The proxy has no hook to release the timer.
+		context.AfterFunc(ctx, cancel)
 
-	if conn, ok := p.grpcConns[name]; ok {
 		return ctx, conn, nil
 	}
 
+	requestid.Logger(ctx, p.logger).Warn("no upstream routed for method", zap.String("method", fullMethodName))
+
 	return nil, nil, apperrors.Internal(errors.New("connection not found"))
 }
 
+func outgoingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+
+	auth.StripMetadata(md)
+
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		md = metadata.Join(md, claims.Metadata())
+	}
+
+	requestid.Outgoing(ctx, md)
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
 func (p *Proxy) AppendInfo(_ bool, resp []byte) ([]byte, error) {
 	return resp, nil
 }
@@ -0,0 +1,121 @@
+package gateway
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/cors"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/deadline"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/ratelimit"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+	"github.com/QuizWars-Ecosystem/go-common/pkg/abstractions"
+)
+
+var _ abstractions.ConfigSubscriber[*config.Config] = (*Gateway)(nil)
+
+func (gt *Gateway) SectionKey() string {
+	return "GATEWAY"
+}
+
+// UpdateConfig applies the settings that are safe to change at runtime.
+func (gt *Gateway) UpdateConfig(cfg *config.Config) error {
+	var rules []*ratelimit.Rule
+	var err error
+
+	if gt.limiter != nil {
+		if rules, err = ratelimit.NewRules(cfg.RateLimit.Rules); err != nil {
+			return err
+		}
+	}
+
+	timeouts, err := deadline.NewPolicy(&cfg.Timeouts)
+	if err != nil {
+		return err
+	}
+
+	var corsPolicy *cors.Policy
+	if gt.cors != nil {
+		if corsPolicy, err = cors.NewPolicy(&cfg.CORS); err != nil {
+			return err
+		}
+	}
+
+	routes, err := gt.buildRoutes(cfg.Upstreams)
+	if err != nil {
+		return err
+	}
+
+	if err = gt.logger.SetLevel(cfg.LogLevel); err != nil {
+		return err
+	}
+
+	if gt.limiter != nil {
+		gt.limiter.SetRules(rules)
+		gt.limiter.SetAPIKeys(cfg.RateLimit.APIKeys)
+	}
+
+	if gt.auth != nil {
+		gt.auth.SetPolicy(auth.NewPolicy(cfg.Auth.PublicMethods))
+	}
+
+	if gt.cors != nil {
+		gt.cors.SetPolicy(corsPolicy)
+	}
+
+	if gt.accessLog != nil {
+		gt.accessLog.SetOptions(&cfg.AccessLog)
+	}
+
+	gt.deadlines.SetPolicy(timeouts)
+	gt.routes.Update(routes)
+	gt.updateHealth()
+
+	gt.logger.Zap().Debug("gateway config applied",
+		zap.String("log_level", gt.logger.Level()),
+		zap.Int("rate_limit_rules", len(rules)),
+		zap.Int("routes", len(routes)),
+	)
+
+	return nil
+}
+
+// buildRoutes maps the configured services onto the connections dialed at startup.
+func (gt *Gateway) buildRoutes(upstreams []config.Upstream) (map[string]string, error) {
+	if len(upstreams) == 0 {
+		upstreams = DefaultUpstreams
+	}
+
+	owners := make(map[string]string)
+	routes := make(map[string]string)
+
+	for _, upstream := range upstreams {
+		if _, ok := gt.grpcConns[upstream.Name]; !ok {
+			gt.logger.Zap().Warn("upstream is not connected until restart", zap.String("upstream", upstream.Name))
+			continue
+		}
+
+		for _, entry := range upstream.Services {
+			if owner, ok := owners[entry]; ok {
+				return nil, fmt.Errorf("service %s is declared by both %s and %s", entry, owner, upstream.Name)
+			}
+
+			owners[entry] = upstream.Name
+			routes[entry] = upstream.Name
+
+			for _, service := range registry.Expand(entry) {
+				if _, ok := gt.httpServices[service]; ok {
+					continue
+				}
+
+				if _, ok := registry.Lookup(service); ok {
+					gt.logger.Zap().Warn("service has no http handler until restart", zap.String("service", service))
+				}
+			}
+		}
+	}
+
+	return routes, nil
+}
@@ -2,6 +2,9 @@ package gateway
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/consul/api"
 	"go.uber.org/zap"
@@ -23,12 +26,14 @@ func NewBuilder(output <-chan []*api.ServiceEntry, logger *zap.Logger) *Builder
 }
 
 type Builder struct {
-	output <-chan []*api.ServiceEntry
-	logger *zap.Logger
+	output   <-chan []*api.ServiceEntry
+	logger   *zap.Logger
+	resolver atomic.Pointer[Resolver]
 }
 
 func (b *Builder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
 	r := NewResolver(target, cc, opts, b.output, b.logger)
+	b.resolver.Store(r)
 
 	go r.watch()
 
@@ -39,13 +44,39 @@ func (b *Builder) Scheme() string {
 	return customScheme
 }
 
+// ResolverState is what the resolver last pushed to its connection.
+type ResolverState struct {
+	Addresses []string  `json:"addresses"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+func (b *Builder) State() ResolverState {
+	r := b.resolver.Load()
+	if r == nil {
+		return ResolverState{}
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	state := ResolverState{UpdatedAt: r.updatedAt}
+	for _, addr := range r.addresses {
+		state.Addresses = append(state.Addresses, addr.Addr)
+	}
+
+	return state
+}
+
 type Resolver struct {
-	target    resolver.Target
-	cc        resolver.ClientConn
-	opts      resolver.BuildOptions
+	target resolver.Target
+	cc     resolver.ClientConn
+	opts   resolver.BuildOptions
+	input  <-chan []*api.ServiceEntry
+	logger *zap.Logger
+
+	mx        sync.Mutex
 	addresses []resolver.Address
-	input     <-chan []*api.ServiceEntry
-	logger    *zap.Logger
+	updatedAt time.Time
 }
 
 func NewResolver(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions, input <-chan []*api.ServiceEntry, logger *zap.Logger) *Resolver {
@@ -80,7 +111,10 @@ func (r *Resolver) update(entries []*api.ServiceEntry) {
 		})
 	}
 
+	r.mx.Lock()
 	r.addresses = addrs
+	r.updatedAt = time.Now()
+	r.mx.Unlock()
 
 	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
 		r.logger.Error("error updating resolver state", zap.String("target", r.target.String()), zap.Error(err))
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
+)
+
+var _ grpc.ClientConnInterface = (*Routes)(nil)
+
+// Routes maps proto services onto upstreams.
+type Routes struct {
+	conns map[string]*grpc.ClientConn
+	table atomic.Pointer[routeTable]
+}
+
+type routeTable struct {
+	services map[string]string
+	packages map[string]string
+}
+
+// NewRoutes routes over conns, keyed by upstream name.
+func NewRoutes(conns map[string]*grpc.ClientConn) *Routes {
+	r := &Routes{conns: conns}
+	r.Update(nil)
+
+	return r
+}
+
+// Update replaces the table of routes to upstream names.
+func (r *Routes) Update(routes map[string]string) {
+	t := &routeTable{
+		services: make(map[string]string, len(routes)),
+		packages: make(map[string]string),
+	}
+
+	for route, upstream := range routes {
+		if pkg, ok := strings.CutSuffix(route, "*"); ok {
+			t.packages[pkg] = upstream
+		} else {
+			t.services[route] = upstream
+		}
+	}
+
+	r.table.Store(t)
+}
+
+// Services lists the route keys mapped to upstream.
+func (r *Routes) Services(upstream string) []string {
+	t := r.table.Load()
+
+	var services []string
+	for service, name := range t.services {
+		if name == upstream {
+			services = append(services, service)
+		}
+	}
+
+	for pkg, name := range t.packages {
+		if name == upstream {
+			services = append(services, pkg+"*")
+		}
+	}
+
+	sort.Strings(services)
+
+	return services
+}
+
+func (r *Routes) Upstream(fullMethodName string) (string, bool) {
+	t := r.table.Load()
+	service := serviceName(fullMethodName)
+
+	if upstream, ok := t.services[service]; ok {
+		return upstream, true
+	}
+
+	var match string
+	for pkg := range t.packages {
+		if strings.HasPrefix(service, pkg) && len(pkg) > len(match) {
+			match = pkg
+		}
+	}
+
+	if match == "" {
+		return "", false
+	}
+
+	return t.packages[match], true
+}
+
+func (r *Routes) Lookup(fullMethodName string) (*grpc.ClientConn, bool) {
+	upstream, ok := r.Upstream(fullMethodName)
+	if !ok {
+		return nil, false
+	}
+
+	conn, ok := r.conns[upstream]
+	return conn, ok
+}
+
+func (r *Routes) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, ok := r.Lookup(method)
+	if !ok {
+		return apperrors.Internal(errors.New("connection not found"))
+	}
+
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+func (r *Routes) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, ok := r.Lookup(method)
+	if !ok {
+		return nil, apperrors.Internal(errors.New("connection not found"))
+	}
+
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+func serviceName(fullMethodName string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethodName, "/"), "/")
+	return service
+}
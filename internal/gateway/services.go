@@ -0,0 +1,124 @@
+package gateway
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/retry"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/tlsconfig"
+)
+
+var DefaultUpstreams = []config.Upstream{
+	{Name: "users-service", Services: []string{"usersservice.v1.*"}},
+	{Name: "questions-service", Services: []string{"questionsservice.v1.*"}},
+}
+
+func NewServiceOptions(upstreams []config.Upstream, logger *zap.Logger) ([]*ServiceOption, error) {
+	if len(upstreams) == 0 {
+		upstreams = DefaultUpstreams
+	}
+
+	owners := make(map[string]string)
+	opts := make([]*ServiceOption, 0, len(upstreams))
+
+	for _, upstream := range upstreams {
+		if upstream.Name == "" {
+			return nil, fmt.Errorf("upstream without name")
+		}
+
+		dialOpts, err := upstreamDialOptions(&upstream.Dial)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %s: %w", upstream.Name, err)
+		}
+
+		retries, err := retry.Build(upstream.Services, upstream.Retries, upstream.IdempotentMethods)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %s: %w", upstream.Name, err)
+		}
+
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(retries.ServiceConfig))
+
+		// grpc-go silently clamps retry policies to the dial option.
+		if retries.MaxAttempts > maxCallAttempts {
+			dialOpts = append(dialOpts, grpc.WithMaxCallAttempts(retries.MaxAttempts))
+		}
+
+		if len(retries.Hedges) > 0 {
+			dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(retry.UnaryClientInterceptor(retries.Hedges, logger)))
+		}
+
+		if len(retries.Skipped) > 0 {
+			logger.Debug("mutating methods are not retried", zap.String("upstream", upstream.Name), zap.Strings("methods", retries.Skipped))
+		}
+
+		opt := &ServiceOption{
+			Address:     upstream.Name,
+			Services:    upstream.Services,
+			Optional:    upstream.Optional,
+			DialOptions: dialOpts,
+		}
+
+		for _, entry := range upstream.Services {
+			if owner, ok := owners[entry]; ok {
+				return nil, fmt.Errorf("service %s is declared by both %s and %s", entry, owner, upstream.Name)
+			}
+
+			owners[entry] = upstream.Name
+
+			for _, service := range registry.Expand(entry) {
+				registerFunc, ok := registry.Lookup(service)
+				if !ok {
+					logger.Warn("service has no http handler, proxy only", zap.String("service", service), zap.String("upstream", upstream.Name))
+					continue
+				}
+
+				opt.RegisterFunc = append(opt.RegisterFunc, registerFunc)
+			}
+		}
+
+		opts = append(opts, opt)
+	}
+
+	return opts, nil
+}
+
+func upstreamDialOptions(cfg *config.Dial) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if cfg.ConnectTimeout > 0 {
+		params := connectParams
+		params.MinConnectTimeout = cfg.ConnectTimeout
+
+		opts = append(opts, grpc.WithConnectParams(params))
+	}
+
+	var callOpts []grpc.CallOption
+
+	if cfg.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+
+	if cfg.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(cfg.MaxSendMsgSize))
+	}
+
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	if cfg.TLS.Enabled {
+		tlsCfg, err := tlsconfig.NewClientConfig(&cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,23 @@
+package grpccode
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Parse accepts both "DeadlineExceeded" and "DEADLINE_EXCEEDED".
+func Parse(name string) (codes.Code, bool) {
+	name = strings.ReplaceAll(name, "_", "")
+	if strings.EqualFold(name, "cancelled") {
+		return codes.Canceled, true
+	}
+
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.EqualFold(c.String(), name) {
+			return c, true
+		}
+	}
+
+	return codes.OK, false
+}
@@ -0,0 +1,63 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LivenessHandler answers as long as the process serves HTTP at all.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// ReadinessHandler answers 503 while a required check fails.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, ready := c.Ready()
+
+		code := http.StatusOK
+		if !ready {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(code)
+
+		if _, verbose := r.URL.Query()["verbose"]; !verbose {
+			if ready {
+				_, _ = w.Write([]byte("ok"))
+			} else {
+				_, _ = w.Write([]byte("not ready"))
+			}
+
+			return
+		}
+
+		var b strings.Builder
+
+		for _, result := range results {
+			switch {
+			case result.OK():
+				fmt.Fprintf(&b, "[+]%s ok\n", result.Name)
+			case result.Optional:
+				fmt.Fprintf(&b, "[!]%s failed (optional): %s\n", result.Name, result.Error)
+			default:
+				fmt.Fprintf(&b, "[-]%s failed: %s\n", result.Name, result.Error)
+			}
+		}
+
+		if ready {
+			b.WriteString("readyz check passed\n")
+		} else {
+			b.WriteString("readyz check failed\n")
+		}
+
+		_, _ = w.Write([]byte(b.String()))
+	})
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var errDraining = errors.New("server is draining")
+
+// Check reports a problem with the gateway by returning an error.
+type Check struct {
+	Name     string
+	Optional bool
+	Func     func() error
+}
+
+type Result struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (r Result) OK() bool {
+	return r.Error == ""
+}
+
+// Checker holds the readiness checks of the gateway and its draining flag.
+type Checker struct {
+	mx       sync.RWMutex
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker() *Checker {
+	c := &Checker{}
+
+	c.Add(Check{Name: "draining", Func: func() error {
+		if c.draining.Load() {
+			return errDraining
+		}
+
+		return nil
+	}})
+
+	return c
+}
+
+func (c *Checker) Add(checks ...Check) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.checks = append(c.checks, checks...)
+}
+
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Ready runs every check and reports whether all required ones passed.
+func (c *Checker) Ready() ([]Result, bool) {
+	c.mx.RLock()
+	checks := c.checks
+	c.mx.RUnlock()
+
+	results := make([]Result, 0, len(checks))
+	ready := true
+
+	for _, check := range checks {
+		result := Result{Name: check.Name, Optional: check.Optional}
+
+		if err := check.Func(); err != nil {
+			result.Error = err.Error()
+
+			if !check.Optional {
+				ready = false
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, ready
+}
@@ -0,0 +1,158 @@
+// Package lb registers a round robin policy that records subchannel states.
+package lb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/resolver"
+)
+
+const Name = "gateway_round_robin"
+
+func init() {
+	balancer.Register(builder{})
+}
+
+// SubConnState is the last state reported for a subchannel.
+type SubConnState struct {
+	Addresses []string  `json:"addresses"`
+	State     string    `json:"state"`
+	LastError string    `json:"last_error,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
+var (
+	balancers = make(map[string]*trackingBalancer)
+	mx        sync.RWMutex
+)
+
+func SubConns(target string) []SubConnState {
+	mx.RLock()
+	b, ok := balancers[target]
+	mx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return b.snapshot()
+}
+
+type builder struct{}
+
+func (builder) Name() string {
+	return Name
+}
+
+func (builder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	b := &trackingBalancer{
+		target: opts.Target.Endpoint(),
+		states: make(map[balancer.SubConn]*SubConnState),
+	}
+
+	b.Balancer = balancer.Get(roundrobin.Name).Build(&trackingClientConn{ClientConn: cc, b: b}, opts)
+
+	mx.Lock()
+	balancers[b.target] = b
+	mx.Unlock()
+
+	return b
+}
+
+type trackingBalancer struct {
+	balancer.Balancer
+	target string
+
+	mx     sync.Mutex
+	states map[balancer.SubConn]*SubConnState
+}
+
+func (b *trackingBalancer) ExitIdle() {
+	if ei, ok := b.Balancer.(balancer.ExitIdler); ok {
+		ei.ExitIdle()
+	}
+}
+
+func (b *trackingBalancer) Close() {
+	mx.Lock()
+	if balancers[b.target] == b {
+		delete(balancers, b.target)
+	}
+	mx.Unlock()
+
+	b.Balancer.Close()
+}
+
+func (b *trackingBalancer) record(sc balancer.SubConn, addrs []resolver.Address, state balancer.SubConnState) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if state.ConnectivityState == connectivity.Shutdown {
+		delete(b.states, sc)
+		return
+	}
+
+	st := &SubConnState{State: state.ConnectivityState.String(), Since: time.Now()}
+	for _, addr := range addrs {
+		st.Addresses = append(st.Addresses, addr.Addr)
+	}
+
+	if state.ConnectionError != nil {
+		st.LastError = state.ConnectionError.Error()
+	}
+
+	b.states[sc] = st
+}
+
+func (b *trackingBalancer) snapshot() []SubConnState {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	out := make([]SubConnState, 0, len(b.states))
+	for _, st := range b.states {
+		out = append(out, *st)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Addresses) == 0 || len(out[j].Addresses) == 0 {
+			return len(out[i].Addresses) < len(out[j].Addresses)
+		}
+
+		return out[i].Addresses[0] < out[j].Addresses[0]
+	})
+
+	return out
+}
+
+// trackingClientConn hooks the state listener of every subchannel.
+type trackingClientConn struct {
+	balancer.ClientConn
+	b *trackingBalancer
+}
+
+func (cc *trackingClientConn) NewSubConn(addrs []resolver.Address, opts balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	var sc balancer.SubConn
+
+	listener := opts.StateListener
+	opts.StateListener = func(state balancer.SubConnState) {
+		cc.b.record(sc, addrs, state)
+
+		if listener != nil {
+			listener(state)
+		}
+	}
+
+	sc, err := cc.ClientConn.NewSubConn(addrs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.b.record(sc, addrs, balancer.SubConnState{ConnectivityState: connectivity.Idle})
+
+	return sc, nil
+}
@@ -0,0 +1,54 @@
+package lockout
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+type clearResponse struct {
+	Cleared bool `json:"cleared"`
+}
+
+func (g *Guard) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.Header().Set("Allow", http.MethodDelete)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+
+		var identity string
+		switch {
+		case query.Get("username") != "":
+			identity = "username:" + normalize(query.Get("username"))
+		case query.Get("email") != "":
+			identity = "email:" + normalize(query.Get("email"))
+		}
+
+		ip := query.Get("ip")
+
+		if identity == "" && ip == "" {
+			http.Error(w, "username, email or ip is required", http.StatusBadRequest)
+			return
+		}
+
+		cleared := g.Clear(identity, ip)
+
+		fields := []zap.Field{zap.String("identity", identity), zap.String("ip", ip), zap.Bool("cleared", cleared)}
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			fields = append(fields, zap.String("by", claims.SubjectID()))
+		}
+
+		requestid.Logger(r.Context(), g.logger).Info("login lock cleared", fields...)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(clearResponse{Cleared: cleared})
+	})
+}
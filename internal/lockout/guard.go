@@ -0,0 +1,295 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/grpccode"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+const LockedError = "too many failed login attempts"
+
+const (
+	scopeIdentity = "identity"
+	scopeIP       = "ip"
+)
+
+type entry struct {
+	failures    int
+	pending     int
+	locks       int
+	lastAttempt time.Time
+	lockedUntil time.Time
+}
+
+type Guard struct {
+	cfg          *config.Lockout
+	failureCodes map[codes.Code]struct{}
+	entries      map[string]*entry
+	mx           sync.Mutex
+	logger       *zap.Logger
+}
+
+func NewGuard(cfg *config.Lockout, logger *zap.Logger) (*Guard, error) {
+	g := &Guard{
+		cfg:          cfg,
+		failureCodes: make(map[codes.Code]struct{}, len(cfg.FailureCodes)),
+		entries:      make(map[string]*entry),
+		logger:       logger,
+	}
+
+	for _, name := range cfg.FailureCodes {
+		code, ok := grpccode.Parse(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown status code %q", name)
+		}
+
+		g.failureCodes[code] = struct{}{}
+	}
+
+	return g, nil
+}
+
+// Run evicts entries without failures for ResetAfter until ctx is done.
+func (g *Guard) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				g.evict(now)
+			}
+		}
+	}()
+}
+
+func (g *Guard) evict(now time.Time) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	for key, e := range g.entries {
+		if e.pending == 0 && now.After(e.lockedUntil) && now.Sub(e.lastAttempt) > g.cfg.ResetAfter {
+			delete(g.entries, key)
+		}
+	}
+
+	activeLocks.Set(float64(g.locked(now)))
+}
+
+// Check reserves the attempt when it returns zero; Record releases it.
+func (g *Guard) Check(identity, ip string) time.Duration {
+	now := time.Now()
+	idKey, addrKey := identityKey(identity), ipKey(ip)
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	wait := max(g.wait(idKey, g.cfg.LockAfter, now), g.wait(addrKey, g.cfg.IPLockAfter, now))
+	if wait > 0 {
+		return wait
+	}
+
+	g.reserve(idKey, now)
+	g.reserve(addrKey, now)
+
+	return 0
+}
+
+// Record releases the attempt reserved by Check and accounts its upstream outcome.
+func (g *Guard) Record(ctx context.Context, identity, ip string, code codes.Code) {
+	now := time.Now()
+	idKey, addrKey := identityKey(identity), ipKey(ip)
+	_, failed := g.failureCodes[code]
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	switch {
+	case code == codes.OK:
+		if e, ok := g.entries[idKey]; ok {
+			e.failures, e.locks, e.lockedUntil = 0, 0, time.Time{}
+		}
+	case failed:
+		g.fail(ctx, idKey, scopeIdentity, g.cfg.LockAfter, now)
+		g.fail(ctx, addrKey, scopeIP, g.cfg.IPLockAfter, now)
+	}
+
+	g.release(idKey)
+	g.release(addrKey)
+}
+
+// Clear removes every failure and lock recorded for identity or ip.
+func (g *Guard) Clear(identity, ip string) bool {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	var cleared bool
+
+	for _, key := range []string{identityKey(identity), ipKey(ip)} {
+		if _, ok := g.entries[key]; ok {
+			delete(g.entries, key)
+			cleared = true
+		}
+	}
+
+	activeLocks.Set(float64(g.locked(time.Now())))
+
+	return cleared
+}
+
+// wait counts attempts in flight, so parallel attempts cannot outrun the lock.
+func (g *Guard) wait(key string, lockAfter int, now time.Time) time.Duration {
+	e, ok := g.entries[key]
+	if !ok {
+		return 0
+	}
+
+	if now.Before(e.lockedUntil) {
+		return e.lockedUntil.Sub(now)
+	}
+
+	attempts := e.failures + e.pending
+
+	if lockAfter > 0 && attempts >= lockAfter {
+		return max(g.cfg.BaseDelay, time.Second)
+	}
+
+	if attempts < g.cfg.DelayAfter {
+		return 0
+	}
+
+	delay := backoff(g.cfg.BaseDelay, attempts-g.cfg.DelayAfter, g.cfg.MaxDelay)
+	if next := e.lastAttempt.Add(delay); now.Before(next) {
+		return next.Sub(now)
+	}
+
+	return 0
+}
+
+func (g *Guard) reserve(key string, now time.Time) {
+	if key == "" {
+		return
+	}
+
+	e, ok := g.entries[key]
+	if !ok {
+		e = &entry{}
+		g.entries[key] = e
+	}
+
+	e.pending++
+	e.lastAttempt = now
+}
+
+// release drops entries that hold nothing but the released attempt.
+func (g *Guard) release(key string) {
+	e, ok := g.entries[key]
+	if !ok {
+		return
+	}
+
+	if e.pending > 0 {
+		e.pending--
+	}
+
+	if e.pending == 0 && e.failures == 0 && e.locks == 0 {
+		delete(g.entries, key)
+	}
+}
+
+func (g *Guard) fail(ctx context.Context, key, scope string, lockAfter int, now time.Time) {
+	if key == "" {
+		return
+	}
+
+	e, ok := g.entries[key]
+	if !ok {
+		e = &entry{}
+		g.entries[key] = e
+	}
+
+	e.failures++
+	e.lastAttempt = now
+
+	if lockAfter <= 0 || e.failures < lockAfter {
+		return
+	}
+
+	e.locks++
+	e.failures = 0
+	e.lockedUntil = now.Add(backoff(g.cfg.LockDuration, e.locks-1, g.cfg.MaxLockDuration))
+
+	lockoutsCounter.WithLabelValues(scope).Inc()
+	activeLocks.Set(float64(g.locked(now)))
+
+	requestid.Logger(ctx, g.logger).Warn("login locked out",
+		zap.String("scope", scope),
+		zap.Int("locks", e.locks),
+		zap.Time("until", e.lockedUntil),
+	)
+}
+
+func (g *Guard) locked(now time.Time) int {
+	var n int
+	for _, e := range g.entries {
+		if now.Before(e.lockedUntil) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Identity extracts the normalized login identifier of req.
+func Identity(req *usersv1.LoginRequest) string {
+	switch id := req.GetIdentifier().(type) {
+	case *usersv1.LoginRequest_Username:
+		return "username:" + normalize(id.Username)
+	case *usersv1.LoginRequest_Email:
+		return "email:" + normalize(id.Email)
+	default:
+		return ""
+	}
+}
+
+func identityKey(identity string) string {
+	if identity == "" {
+		return ""
+	}
+
+	return "id:" + identity
+}
+
+func ipKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+
+	return "ip:" + ip
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func backoff(base time.Duration, exp int, limit time.Duration) time.Duration {
+	d := base
+	for i := 0; i < exp && d < limit; i++ {
+		d *= 2
+	}
+
+	return min(d, limit)
+}
@@ -0,0 +1,64 @@
+package lockout
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/proxystream"
+)
+
+func (g *Guard) StreamServerInterceptor(trustForwarded bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if info.FullMethod != usersv1.UsersAuthService_Login_FullMethodName {
+			return handler(srv, ss)
+		}
+
+		msg, stream, err := proxystream.Peek(ss, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		var identity string
+		if req, ok := msg.(*usersv1.LoginRequest); ok {
+			identity = Identity(req)
+		}
+
+		ip := clientip.FromContext(ss.Context(), trustForwarded)
+
+		if wait := g.Check(identity, ip); wait > 0 {
+			_ = ss.SetHeader(metadata.Pairs(strings.ToLower(RetryAfterHeader), retryAfter(wait)))
+			return status.Error(codes.ResourceExhausted, LockedError)
+		}
+
+		defer func() { g.Record(ss.Context(), identity, ip, status.Code(err)) }()
+
+		return handler(srv, stream)
+	}
+}
+
+type resultKey struct{}
+
+// result carries the Login status from the client call back to the HTTP middleware.
+type result struct {
+	code    codes.Code
+	invoked bool
+}
+
+func (g *Guard) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		if res, ok := ctx.Value(resultKey{}).(*result); ok && method == usersv1.UsersAuthService_Login_FullMethodName {
+			res.code, res.invoked = status.Code(err), true
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,27 @@
+package lockout
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	lockoutsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_login_lockouts_total",
+			Help: "Total number of login lockouts applied by the gateway",
+		},
+		[]string{"scope"},
+	)
+
+	activeLocks = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gateway_login_active_lockouts",
+			Help: "Number of identities and addresses currently locked out",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		lockoutsCounter,
+		activeLocks,
+	)
+}
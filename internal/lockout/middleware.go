@@ -0,0 +1,87 @@
+package lockout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+	"github.com/QuizWars-Ecosystem/go-common/pkg/grpcx/errors"
+)
+
+const (
+	RetryAfterHeader = "Retry-After"
+
+	BodyTooLargeError = "login request too large"
+
+	maxLoginBodySize = 64 << 10
+)
+
+var unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+func NewMiddleware(guard *Guard, trustForwarded bool, errHandler runtime.ErrorHandlerFunc) runtime.Middleware {
+	marshaler := &runtime.JSONPb{}
+
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if auth.MethodFromRequest(r) != usersv1.UsersAuthService_Login_FullMethodName {
+				handlerFunc(w, r, pathParams)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxLoginBodySize+1))
+			if err != nil {
+				errHandler(r.Context(), nil, marshaler, w, r, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+
+			if len(body) > maxLoginBodySize {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				_ = json.NewEncoder(w).Encode(errors.HTTPError{Message: BodyTooLargeError})
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var req usersv1.LoginRequest
+			_ = unmarshaler.Unmarshal(body, &req)
+
+			identity := Identity(&req)
+			ip := clientip.FromRequest(r, trustForwarded)
+
+			if wait := guard.Check(identity, ip); wait > 0 {
+				w.Header().Set(RetryAfterHeader, retryAfter(wait))
+				errHandler(r.Context(), nil, marshaler, w, r, status.Error(codes.ResourceExhausted, LockedError))
+				return
+			}
+
+			res := &result{}
+			r = r.WithContext(context.WithValue(r.Context(), resultKey{}, res))
+
+			defer func() {
+				if res.invoked {
+					guard.Record(r.Context(), identity, ip, res.code)
+				}
+			}()
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
+
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
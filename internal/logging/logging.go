@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/QuizWars-Ecosystem/go-common/pkg/log"
+	"github.com/hashicorp/go-hclog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger wraps the common logger with a level that can be changed at runtime.
+type Logger struct {
+	*log.Logger
+	zap   *zap.Logger
+	level zap.AtomicLevel
+}
+
+func NewLogger(local bool, level string) *Logger {
+	base := log.NewLogger(local, zapcore.DebugLevel.String())
+
+	atomicLevel := zap.NewAtomicLevelAt(parseLevel(level))
+
+	return &Logger{
+		Logger: base,
+		zap:    base.Zap().WithOptions(zap.IncreaseLevel(atomicLevel)),
+		level:  atomicLevel,
+	}
+}
+
+func (l *Logger) Zap() *zap.Logger {
+	return l.zap
+}
+
+func (l *Logger) HCLogger() hclog.Logger {
+	logger := hclog.NewInterceptLogger(&hclog.LoggerOptions{Output: io.Discard})
+	logger.RegisterSink(zapSink{logger: l.zap})
+
+	return logger
+}
+
+type zapSink struct {
+	logger *zap.Logger
+}
+
+func (s zapSink) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	fields := make([]zap.Field, 0, len(args)/2+1)
+	if name != "" {
+		fields = append(fields, zap.String("component", name))
+	}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		fields = append(fields, zap.Any(fmt.Sprint(args[i]), args[i+1]))
+	}
+
+	switch level {
+	case hclog.Trace, hclog.Debug:
+		s.logger.Debug(msg, fields...)
+	case hclog.Info:
+		s.logger.Info(msg, fields...)
+	case hclog.Warn:
+		s.logger.Warn(msg, fields...)
+	default:
+		s.logger.Error(msg, fields...)
+	}
+}
+
+func (l *Logger) Level() string {
+	return l.level.Level().String()
+}
+
+func (l *Logger) SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	l.level.SetLevel(lvl)
+
+	return nil
+}
+
+func parseLevel(level string) zapcore.Level {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+
+	return lvl
+}
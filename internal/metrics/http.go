@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/middlewares"
+)
+
+// unmatched keeps arbitrary paths out of the route label.
+const unmatched = "unmatched"
+
+// otherMethod labels non-standard methods, which callers choose freely.
+const otherMethod = "other"
+
+type labelsKey struct{}
+
+// labels carries what only the runtime mux knows back to the handler.
+type labels struct {
+	route    string
+	upstream string
+}
+
+// Handler observes every request once the response is written.
+func (m *HTTP) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		l := &labels{route: unmatched}
+		r = r.WithContext(context.WithValue(r.Context(), labelsKey{}, l))
+
+		body := middlewares.NewCountingReader(r.Body)
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+
+		rec := middlewares.NewStatusRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		exemplar := m.exemplar(r.Context())
+		method := methodLabel(r.Method)
+		status := statusClass(rec.Status())
+
+		add(m.requests.WithLabelValues(l.route, method, l.upstream, status), exemplar)
+		observe(m.duration.WithLabelValues(l.route, method, l.upstream, status), time.Since(start).Seconds(), exemplar)
+		observe(m.requestSize.WithLabelValues(l.route, method, l.upstream), float64(body.Bytes()), exemplar)
+		observe(m.responseSize.WithLabelValues(l.route, method, l.upstream), float64(rec.Bytes()), exemplar)
+	})
+}
+
+func (m *HTTP) Middleware() runtime.Middleware {
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			l, ok := r.Context().Value(labelsKey{}).(*labels)
+			if !ok {
+				l = &labels{route: unmatched}
+			}
+
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				l.route = pattern.String()
+			}
+
+			if upstream, ok := m.upstream(auth.MethodFromRequest(r)); ok {
+				l.upstream = upstream
+			}
+
+			gauge := m.inFlight.WithLabelValues(l.route, methodLabel(r.Method), l.upstream)
+			gauge.Inc()
+			defer gauge.Dec()
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
+
+func (m *HTTP) exemplar(ctx context.Context) prometheus.Labels {
+	if !m.exemplars {
+		return nil
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsSampled() {
+		return prometheus.Labels{"trace_id": sc.TraceID().String()}
+	}
+
+	return nil
+}
+
+func add(c prometheus.Counter, exemplar prometheus.Labels) {
+	if a, ok := c.(prometheus.ExemplarAdder); ok && exemplar != nil {
+		a.AddWithExemplar(1, exemplar)
+		return
+	}
+
+	c.Inc()
+}
+
+func observe(o prometheus.Observer, v float64, exemplar prometheus.Labels) {
+	if e, ok := o.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		e.ObserveWithExemplar(v, exemplar)
+		return
+	}
+
+	o.Observe(v)
+}
+
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
+
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
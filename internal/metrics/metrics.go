@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+type HTTP struct {
+	upstream  func(fullMethodName string) (string, bool)
+	exemplars bool
+
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	inFlight     *prometheus.GaugeVec
+}
+
+func New(cfg *config.Metrics, upstream func(fullMethodName string) (string, bool)) (*HTTP, error) {
+	if !sort.Float64sAreSorted(cfg.Buckets) || !sort.Float64sAreSorted(cfg.SizeBuckets) {
+		return nil, fmt.Errorf("histogram buckets must be in increasing order")
+	}
+
+	m := &HTTP{
+		upstream:  upstream,
+		exemplars: cfg.Exemplars,
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gateway_http_requests_total",
+				Help: "Total number of HTTP requests served by the runtime mux",
+			},
+			[]string{"route", "method", "upstream", "status"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gateway_http_request_duration_seconds",
+				Help:    "Duration of HTTP requests served by the runtime mux",
+				Buckets: cfg.Buckets,
+			},
+			[]string{"route", "method", "upstream", "status"},
+		),
+		requestSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gateway_http_request_size_bytes",
+				Help:    "Size of HTTP request bodies read by the runtime mux",
+				Buckets: cfg.SizeBuckets,
+			},
+			[]string{"route", "method", "upstream"},
+		),
+		responseSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gateway_http_response_size_bytes",
+				Help:    "Size of HTTP response bodies written by the runtime mux",
+				Buckets: cfg.SizeBuckets,
+			},
+			[]string{"route", "method", "upstream"},
+		),
+		inFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gateway_http_requests_in_flight",
+				Help: "Number of HTTP requests currently served by the runtime mux",
+			},
+			[]string{"route", "method", "upstream"},
+		),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.requestSize, m.responseSize, m.inFlight} {
+		if err := prometheus.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	grpcprometheus.EnableHandlingTimeHistogram(grpcprometheus.WithHistogramBuckets(cfg.Buckets))
+	grpcprometheus.EnableClientHandlingTimeHistogram(grpcprometheus.WithHistogramBuckets(cfg.Buckets))
+
+	return m, nil
+}
+
+// Exemplars are only exposed in the OpenMetrics format.
+func Handler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)
+}
@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+)
+
+// StatusRecorder captures the status code and body size written by a handler.
+type StatusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w}
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err
+}
+
+func (r *StatusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the written status, or 200 when the handler wrote nothing.
+func (r *StatusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+func (r *StatusRecorder) Bytes() int {
+	return r.bytes
+}
+
+// CountingReader counts the bytes of a request body the handler read.
+type CountingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func NewCountingReader(body io.ReadCloser) *CountingReader {
+	return &CountingReader{ReadCloser: body}
+}
+
+func (r *CountingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+
+	return n, err
+}
+
+func (r *CountingReader) Bytes() int64 {
+	return r.n
+}
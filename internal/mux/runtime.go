@@ -2,14 +2,14 @@ package mux
 
 import (
 	"net/http"
-)
 
-func RegisterRuntimeMux(mux *http.ServeMux) error {
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/health"
+)
 
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+func RegisterRuntimeMux(mux *http.ServeMux, checker *health.Checker) error {
+	mux.Handle("/livez", health.LivenessHandler())
+	mux.Handle("/healthz", health.LivenessHandler())
+	mux.Handle("/readyz", checker.ReadinessHandler())
 
 	return nil
 }
@@ -0,0 +1,417 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/rest"
+)
+
+const (
+	errorSchema    = "Error"
+	securityScheme = "bearerAuth"
+	contentType    = "application/json"
+
+	maxQueryDepth = 3
+)
+
+var pathVariable = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+type Builder struct {
+	doc      *Document
+	isPublic func(fullMethod string) bool
+	ids      map[string]int
+}
+
+func NewBuilder(title, version string, isPublic func(fullMethod string) bool) *Builder {
+	if isPublic == nil {
+		isPublic = func(string) bool { return false }
+	}
+
+	return &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    Info{Title: title, Version: version},
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas: map[string]*Schema{
+					errorSchema: {
+						Type: "object",
+						Properties: map[string]*Schema{
+							"message":     {Type: "string"},
+							"incident_id": {Type: "string", Description: "Set for internal errors, to correlate with the gateway logs."},
+						},
+						Required: []string{"message"},
+					},
+				},
+				SecuritySchemes: map[string]SecurityScheme{
+					securityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+			Security: []SecurityRequirement{{securityScheme: {}}},
+		},
+		isPublic: isPublic,
+		ids:      make(map[string]int),
+	}
+}
+
+func (b *Builder) AddService(service string) error {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return fmt.Errorf("%s is not a service", service)
+	}
+
+	for i := 0; i < sd.Methods().Len(); i++ {
+		md := sd.Methods().Get(i)
+		if md.IsStreamingClient() {
+			continue
+		}
+
+		fullMethod := fmt.Sprintf("/%s/%s", sd.FullName(), md.Name())
+
+		op := b.operation(fullMethod, md)
+		op.RequestBody = b.requestBody(md.Input())
+
+		b.add(http.MethodPost, fullMethod, op)
+	}
+
+	return nil
+}
+
+// AddBinding adds a route declared in the HTTP rule file.
+func (b *Builder) AddBinding(binding rest.Binding) error {
+	md, err := registry.FindMethod(binding.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	input := md.Input()
+	op := b.operation(binding.FullMethod, md)
+	bound := make(map[string]struct{})
+
+	for _, match := range pathVariable.FindAllStringSubmatch(binding.Template, -1) {
+		name := match[1]
+		bound[name] = struct{}{}
+
+		schema := &Schema{Type: "string"}
+		if fd := fieldByPath(input, name); fd != nil {
+			schema = b.fieldSchema(fd)
+		}
+
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	switch binding.Body {
+	case "":
+		op.Parameters = append(op.Parameters, b.queryParameters(input, "", bound, 0)...)
+	case "*":
+		op.RequestBody = b.requestBody(input)
+	default:
+		fd := input.Fields().ByName(protoreflect.Name(binding.Body))
+		if fd == nil || fd.Message() == nil {
+			return fmt.Errorf("%s: body %q is not a message field", binding.FullMethod, binding.Body)
+		}
+
+		bound[string(fd.Name())] = struct{}{}
+		op.RequestBody = b.requestBody(fd.Message())
+		op.Parameters = append(op.Parameters, b.queryParameters(input, "", bound, 0)...)
+	}
+
+	b.add(binding.HTTPMethod, pathVariable.ReplaceAllString(binding.Template, "{$1}"), op)
+
+	return nil
+}
+
+func (b *Builder) Document() *Document {
+	sort.Slice(b.doc.Tags, func(i, j int) bool {
+		return b.doc.Tags[i].Name < b.doc.Tags[j].Name
+	})
+
+	return b.doc
+}
+
+func (b *Builder) add(method, path string, op *Operation) {
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		b.doc.Paths[path] = item
+	}
+
+	item[strings.ToLower(method)] = op
+}
+
+func (b *Builder) operation(fullMethod string, md protoreflect.MethodDescriptor) *Operation {
+	service := md.Parent().(protoreflect.ServiceDescriptor)
+	tag := string(service.Name())
+
+	id := fmt.Sprintf("%s_%s", service.Name(), md.Name())
+	if n := b.ids[id]; n > 0 {
+		b.ids[id]++
+		id = fmt.Sprintf("%s%d", id, n)
+	} else {
+		b.ids[id] = 1
+		b.addTag(tag)
+	}
+
+	op := &Operation{
+		OperationID: id,
+		Summary:     fullMethod,
+		Tags:        []string{tag},
+		Responses: map[string]Response{
+			"200": {
+				Description: "A successful response.",
+				Content:     map[string]MediaType{contentType: {Schema: b.messageSchema(md.Output())}},
+			},
+			"default": {
+				Description: "An error response.",
+				Content:     map[string]MediaType{contentType: {Schema: ref(errorSchema)}},
+			},
+		},
+	}
+
+	if b.isPublic(fullMethod) {
+		op.Security = &[]SecurityRequirement{}
+	}
+
+	return op
+}
+
+func (b *Builder) addTag(name string) {
+	for _, tag := range b.doc.Tags {
+		if tag.Name == name {
+			return
+		}
+	}
+
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name})
+}
+
+func (b *Builder) requestBody(md protoreflect.MessageDescriptor) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{contentType: {Schema: b.messageSchema(md)}},
+	}
+}
+
+// queryParameters follows runtime.PopulateQueryParameters.
+func (b *Builder) queryParameters(md protoreflect.MessageDescriptor, prefix string, bound map[string]struct{}, depth int) []Parameter {
+	var params []Parameter
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := prefix + fd.JSONName()
+
+		if _, ok := bound[prefix+string(fd.Name())]; ok {
+			continue
+		}
+
+		if _, ok := bound[name]; ok {
+			continue
+		}
+
+		if fd.IsMap() {
+			continue
+		}
+
+		if fd.Kind() == protoreflect.MessageKind {
+			if wellKnown(fd.Message()) != nil {
+				params = append(params, Parameter{Name: name, In: "query", Schema: b.fieldSchema(fd)})
+				continue
+			}
+
+			if !fd.IsList() && depth < maxQueryDepth {
+				params = append(params, b.queryParameters(fd.Message(), name+".", bound, depth+1)...)
+			}
+
+			continue
+		}
+
+		params = append(params, Parameter{Name: name, In: "query", Schema: b.fieldSchema(fd)})
+	}
+
+	return params
+}
+
+func (b *Builder) fieldSchema(fd protoreflect.FieldDescriptor) *Schema {
+	if fd.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: b.singularSchema(fd.MapValue())}
+	}
+
+	schema := b.singularSchema(fd)
+	if fd.IsList() {
+		return &Schema{Type: "array", Items: schema}
+	}
+
+	return schema
+}
+
+func (b *Builder) singularSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	// protojson writes 64-bit integers as strings.
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "string", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		return b.enumSchema(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.messageSchema(fd.Message())
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func (b *Builder) enumSchema(ed protoreflect.EnumDescriptor) *Schema {
+	name := string(ed.FullName())
+
+	if _, ok := b.doc.Components.Schemas[name]; !ok {
+		values := ed.Values()
+		schema := &Schema{Type: "string", Enum: make([]string, 0, values.Len())}
+
+		for i := 0; i < values.Len(); i++ {
+			schema.Enum = append(schema.Enum, string(values.Get(i).Name()))
+		}
+
+		b.doc.Components.Schemas[name] = schema
+	}
+
+	return ref(name)
+}
+
+// messageSchema registers md as a component and returns a reference to it.
+func (b *Builder) messageSchema(md protoreflect.MessageDescriptor) *Schema {
+	if schema := wellKnown(md); schema != nil {
+		return schema
+	}
+
+	name := string(md.FullName())
+	if _, ok := b.doc.Components.Schemas[name]; ok {
+		return ref(name)
+	}
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	// Registered before the fields, so recursive messages end in a reference.
+	b.doc.Components.Schemas[name] = schema
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		schema.Properties[fd.JSONName()] = b.fieldSchema(fd)
+	}
+
+	var oneofs []*Schema
+
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		od := md.Oneofs().Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+
+		members := make([]*Schema, 0, od.Fields().Len())
+		names := make([]string, 0, od.Fields().Len())
+
+		for j := 0; j < od.Fields().Len(); j++ {
+			member := od.Fields().Get(j).JSONName()
+			members = append(members, &Schema{Required: []string{member}})
+			names = append(names, member)
+		}
+
+		oneofs = append(oneofs, &Schema{
+			Description: fmt.Sprintf("%s: exactly one of %s.", od.Name(), strings.Join(names, ", ")),
+			OneOf:       members,
+		})
+	}
+
+	switch len(oneofs) {
+	case 0:
+	case 1:
+		schema.Description = oneofs[0].Description
+		schema.OneOf = oneofs[0].OneOf
+	default:
+		schema.AllOf = oneofs
+	}
+
+	return ref(name)
+}
+
+func wellKnown(md protoreflect.MessageDescriptor) *Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return &Schema{Type: "string"}
+	case "google.protobuf.Empty", "google.protobuf.Struct", "google.protobuf.Any":
+		return &Schema{Type: "object"}
+	case "google.protobuf.ListValue":
+		return &Schema{Type: "array", Items: &Schema{}}
+	case "google.protobuf.Value":
+		return &Schema{}
+	case "google.protobuf.StringValue":
+		return &Schema{Type: "string", Nullable: true}
+	case "google.protobuf.BytesValue":
+		return &Schema{Type: "string", Format: "byte", Nullable: true}
+	case "google.protobuf.BoolValue":
+		return &Schema{Type: "boolean", Nullable: true}
+	case "google.protobuf.Int32Value":
+		return &Schema{Type: "integer", Format: "int32", Nullable: true}
+	case "google.protobuf.UInt32Value":
+		return &Schema{Type: "integer", Format: "int64", Nullable: true}
+	case "google.protobuf.Int64Value":
+		return &Schema{Type: "string", Format: "int64", Nullable: true}
+	case "google.protobuf.UInt64Value":
+		return &Schema{Type: "string", Format: "uint64", Nullable: true}
+	case "google.protobuf.FloatValue":
+		return &Schema{Type: "number", Format: "float", Nullable: true}
+	case "google.protobuf.DoubleValue":
+		return &Schema{Type: "number", Format: "double", Nullable: true}
+	}
+
+	return nil
+}
+
+// fieldByPath resolves a dotted path variable ("a.b") to its field.
+func fieldByPath(md protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+
+	for _, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil
+		}
+
+		if fd = md.Fields().ByName(protoreflect.Name(name)); fd == nil {
+			if fd = md.Fields().ByJSONName(name); fd == nil {
+				return nil
+			}
+		}
+
+		md = fd.Message()
+	}
+
+	return fd
+}
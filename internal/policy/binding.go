@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/proxystream"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
+)
+
+const SubjectMismatchError = "request subject does not match token subject"
+
+type BindMode int
+
+const (
+	// Overwrite replaces the field with the token subject unconditionally.
+	Overwrite BindMode = iota
+	// Reject fills an empty field and refuses any other value.
+	Reject
+)
+
+type Binding struct {
+	Field protoreflect.Name
+	Mode  BindMode
+}
+
+var DefaultBindings = map[string]Binding{
+	usersv1.UsersAuthService_Logout_FullMethodName:            {Field: "user_id", Mode: Overwrite},
+	usersv1.UsersAuthService_LinkOAuthProvider_FullMethodName: {Field: "user_id", Mode: Overwrite},
+	usersv1.UsersProfileService_UpdateProfile_FullMethodName:  {Field: "user_id", Mode: Reject},
+	usersv1.UsersProfileService_UpdateAvatar_FullMethodName:   {Field: "user_id", Mode: Reject},
+	usersv1.UsersProfileService_ChangePassword_FullMethodName: {Field: "user_id", Mode: Reject},
+	usersv1.UsersProfileService_DeleteAccount_FullMethodName:  {Field: "user_id", Mode: Reject},
+	usersv1.UsersSocialService_AddFriend_FullMethodName:       {Field: "requester_id", Mode: Overwrite},
+	usersv1.UsersSocialService_AcceptFriend_FullMethodName:    {Field: "recipient_id", Mode: Overwrite},
+	usersv1.UsersSocialService_RejectFriend_FullMethodName:    {Field: "recipient_id", Mode: Overwrite},
+	usersv1.UsersSocialService_RemoveFriend_FullMethodName:    {Field: "requester_id", Mode: Overwrite},
+	usersv1.UsersSocialService_BlockFriend_FullMethodName:     {Field: "user_id", Mode: Overwrite},
+	usersv1.UsersSocialService_UnblockFriend_FullMethodName:   {Field: "user_id", Mode: Overwrite},
+}
+
+// Binder ties user identifiers in request bodies to the authenticated subject.
+type Binder struct {
+	bindings map[string]Binding
+	logger   *zap.Logger
+}
+
+func NewBinder(bindings map[string]Binding, logger *zap.Logger) *Binder {
+	return &Binder{
+		bindings: bindings,
+		logger:   logger,
+	}
+}
+
+// Bind applies the binding of fullMethod to msg, reporting whether msg was modified.
+func (b *Binder) Bind(ctx context.Context, fullMethod string, msg proto.Message) (bool, error) {
+	binding, ok := b.bindings[fullMethod]
+	if !ok {
+		return false, nil
+	}
+
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	m := msg.ProtoReflect()
+
+	fd := m.Descriptor().Fields().ByName(binding.Field)
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return false, apperrors.Internal(fmt.Errorf("invalid binding field %q for %s", binding.Field, fullMethod))
+	}
+
+	subject := claims.SubjectID()
+	current := m.Get(fd).String()
+
+	if current == subject {
+		return false, nil
+	}
+
+	if binding.Mode == Reject && current != "" {
+		requestid.Logger(ctx, b.logger).Debug("subject mismatch",
+			zap.String("method", fullMethod),
+			zap.String("field", string(binding.Field)),
+			zap.String("subject", subject),
+		)
+
+		return false, apperrors.Forbidden(SubjectMismatchError)
+	}
+
+	m.Set(fd, protoreflect.ValueOfString(subject))
+
+	return true, nil
+}
+
+// UnaryClientInterceptor binds requests issued by the runtime mux handlers.
+func (b *Binder) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if msg, ok := req.(proto.Message); ok {
+			if _, err := b.Bind(ctx, method, msg); err != nil {
+				return err
+			}
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamServerInterceptor binds requests forwarded by the transparent proxy.
+func (b *Binder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := b.bindings[info.FullMethod]; !ok {
+			return handler(srv, ss)
+		}
+
+		msg, stream, err := proxystream.Peek(ss, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		changed, err := b.Bind(ss.Context(), info.FullMethod, msg)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			if err = stream.Replace(msg); err != nil {
+				return apperrors.Internal(err)
+			}
+		}
+
+		return handler(srv, stream)
+	}
+}
@@ -0,0 +1,165 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+	apperrors "github.com/QuizWars-Ecosystem/go-common/pkg/error"
+)
+
+const PermissionDeniedError = "permission denied"
+
+var DefaultRoles = map[string]usersv1.Role{
+	servicePrefix(usersv1.UsersAdminService_ServiceDesc):         usersv1.Role_ROLE_ADMIN,
+	usersv1.UsersAdminService_UpdateUserRole_FullMethodName:      usersv1.Role_ROLE_SUPER,
+	servicePrefix(questionsv1.QuestionsAdminService_ServiceDesc): usersv1.Role_ROLE_ADMIN,
+}
+
+type RBAC struct {
+	methods  map[string]usersv1.Role
+	prefixes map[string]usersv1.Role
+	logger   *zap.Logger
+}
+
+func NewRBAC(table map[string]usersv1.Role, logger *zap.Logger) *RBAC {
+	r := &RBAC{
+		methods:  make(map[string]usersv1.Role),
+		prefixes: make(map[string]usersv1.Role),
+		logger:   logger,
+	}
+
+	for key, role := range table {
+		if strings.HasSuffix(key, "/") {
+			r.prefixes[key] = role
+		} else {
+			r.methods[key] = role
+		}
+	}
+
+	return r
+}
+
+// Required returns the minimal role for fullMethod.
+func (r *RBAC) Required(fullMethod string) (usersv1.Role, bool) {
+	if role, ok := r.methods[fullMethod]; ok {
+		return role, true
+	}
+
+	var match string
+	for prefix := range r.prefixes {
+		if strings.HasPrefix(fullMethod, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+
+	if match == "" {
+		return usersv1.Role_ROLE_UNSPECIFIED, false
+	}
+
+	return r.prefixes[match], true
+}
+
+func (r *RBAC) Authorize(ctx context.Context, fullMethod string) error {
+	required, ok := r.Required(fullMethod)
+	if !ok {
+		return nil
+	}
+
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return apperrors.Unauthorized(auth.AccessTokenNotProvidedError)
+	}
+
+	role := ParseRole(claims.Role)
+	if role >= required {
+		return nil
+	}
+
+	requestid.Logger(ctx, r.logger).Debug("role denied",
+		zap.String("method", fullMethod),
+		zap.String("subject", claims.SubjectID()),
+		zap.String("role", role.String()),
+		zap.String("required", required.String()),
+	)
+
+	return apperrors.Forbidden(fmt.Sprintf("%s: %s required", PermissionDeniedError, required.String()))
+}
+
+func (r *RBAC) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := r.Authorize(ctx, method); err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (r *RBAC) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.Authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func servicePrefix(desc grpc.ServiceDesc) string {
+	return "/" + desc.ServiceName + "/"
+}
+
+// ParseRole accepts "ROLE_ADMIN" as well as "admin".
+func ParseRole(role string) usersv1.Role {
+	role = strings.ToUpper(strings.TrimSpace(role))
+
+	if v, ok := usersv1.Role_value[role]; ok {
+		return usersv1.Role(v)
+	}
+
+	if v, ok := usersv1.Role_value["ROLE_"+role]; ok {
+		return usersv1.Role(v)
+	}
+
+	return usersv1.Role_ROLE_UNSPECIFIED
+}
+
+func RequireRole(authenticator *auth.Authenticator, role usersv1.Role, errHandler runtime.ErrorHandlerFunc, next http.Handler) http.Handler {
+	marshaler := &runtime.JSONPb{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if authenticator == nil {
+			errHandler(ctx, nil, marshaler, w, r, apperrors.Forbidden(PermissionDeniedError))
+			return
+		}
+
+		claims, err := authenticator.Authenticate(ctx, r.URL.Path, r.Header.Get(auth.AuthorizationHeader))
+		if err == nil && claims == nil {
+			err = apperrors.Unauthorized(auth.AccessTokenNotProvidedError)
+		}
+
+		if err != nil {
+			errHandler(ctx, nil, marshaler, w, r, err)
+			return
+		}
+
+		if ParseRole(claims.Role) < role {
+			errHandler(ctx, nil, marshaler, w, r, apperrors.Forbidden(fmt.Sprintf("%s: %s required", PermissionDeniedError, role.String())))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(ctx, claims)))
+	})
+}
@@ -0,0 +1,120 @@
+package proxystream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/siderolabs/grpc-proxy/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var codec = proxy.Codec()
+
+func InputType(fullMethod string) (protoreflect.MessageType, error) {
+	md, err := methodDescriptor(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+}
+
+// OutputType resolves the response message type of fullMethod.
+func OutputType(fullMethod string) (protoreflect.MessageType, error) {
+	md, err := methodDescriptor(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+}
+
+func methodDescriptor(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("malformed method name %q", fullMethod)
+	}
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", service)
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, fmt.Errorf("method %q not found", fullMethod)
+	}
+
+	return md, nil
+}
+
+// Stream replays a request frame already read from the wrapped stream.
+type Stream struct {
+	grpc.ServerStream
+	first    any
+	consumed bool
+}
+
+// Peek receives and decodes the first frame of ss.
+func Peek(ss grpc.ServerStream, fullMethod string) (proto.Message, *Stream, error) {
+	mt, err := InputType(fullMethod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frame := proxy.NewFrame(nil)
+	if err = ss.RecvMsg(frame); err != nil {
+		return nil, nil, err
+	}
+
+	msg := mt.New().Interface()
+	if err = Decode(frame, msg); err != nil {
+		return nil, nil, err
+	}
+
+	return msg, &Stream{ServerStream: ss, first: frame}, nil
+}
+
+// Replace re-encodes msg as the frame the proxied call will forward.
+func (s *Stream) Replace(msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(mem.BufferSlice{mem.SliceBuffer(data)}, s.first)
+}
+
+func (s *Stream) RecvMsg(m any) error {
+	if s.consumed {
+		return s.ServerStream.RecvMsg(m)
+	}
+
+	s.consumed = true
+
+	data, err := codec.Marshal(s.first)
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(data, m)
+}
+
+// Decode unmarshals a raw proxy frame into msg.
+func Decode(frame any, msg proto.Message) error {
+	data, err := codec.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data.Materialize(), msg)
+}
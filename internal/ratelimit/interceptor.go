@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+)
+
+func (l *Limiter) StreamServerInterceptor(trustForwarded bool) grpc.StreamServerInterceptor {
+	apiKey := strings.ToLower(APIKeyHeader)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+
+		id := Identity{
+			IP: clientip.FromContext(ctx, trustForwarded),
+		}
+
+		if values := metadata.ValueFromIncomingContext(ctx, apiKey); len(values) > 0 {
+			id.APIKey = l.APIKey(values[0])
+		}
+
+		if claims, ok := auth.ClaimsFromContext(ctx); ok {
+			id.UserID = claims.SubjectID()
+		}
+
+		res := l.Check(ctx, info.FullMethod, id)
+		if res == nil {
+			return handler(srv, ss)
+		}
+
+		md := metadata.Pairs(
+			strings.ToLower(LimitHeader), strconv.Itoa(res.Limit),
+			strings.ToLower(RemainingHeader), strconv.Itoa(res.Remaining),
+			strings.ToLower(ResetHeader), ceilSeconds(res.Reset),
+		)
+
+		if !res.Allowed {
+			md.Set(strings.ToLower(RetryAfterHeader), ceilSeconds(res.RetryAfter))
+			_ = ss.SetHeader(md)
+
+			return status.Error(codes.ResourceExhausted, RateLimitedError)
+		}
+
+		_ = ss.SetHeader(md)
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,325 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+type KeyKind string
+
+const (
+	KeyIP     KeyKind = "ip"
+	KeyUser   KeyKind = "user"
+	KeyAPIKey KeyKind = "api_key"
+)
+
+var DefaultRules = []config.RateLimitRule{
+	{
+		Name:      "auth",
+		Methods:   []string{usersv1.UsersAuthService_Login_FullMethodName, usersv1.UsersAuthService_Register_FullMethodName},
+		Key:       string(KeyIP),
+		Algorithm: string(SlidingWindow),
+		Limit:     10,
+		Window:    time.Minute,
+	},
+	{
+		Name:      "question-batch",
+		Methods:   []string{questionsv1.QuestionsService_GetQuestionBatch_FullMethodName},
+		Key:       string(KeyUser),
+		Algorithm: string(TokenBucket),
+		Limit:     5,
+		Window:    time.Second,
+		Burst:     10,
+	},
+}
+
+// Identity carries every attribute a rule may be keyed by.
+type Identity struct {
+	IP     string
+	UserID string
+	APIKey string
+}
+
+type Rule struct {
+	Name      string
+	Key       KeyKind
+	Algorithm Algorithm
+	Limit     int
+	Window    time.Duration
+	Burst     int
+	methods   map[string]struct{}
+	prefixes  []string
+}
+
+func NewRule(cfg config.RateLimitRule) (*Rule, error) {
+	r := &Rule{
+		Name:      cfg.Name,
+		Key:       KeyKind(cfg.Key),
+		Algorithm: Algorithm(cfg.Algorithm),
+		Limit:     cfg.Limit,
+		Window:    cfg.Window,
+		Burst:     cfg.Burst,
+		methods:   make(map[string]struct{}),
+	}
+
+	if r.Key == "" {
+		r.Key = KeyIP
+	}
+
+	if r.Algorithm == "" {
+		r.Algorithm = TokenBucket
+	}
+
+	if r.Burst <= 0 {
+		r.Burst = r.Limit
+	}
+
+	switch {
+	case r.Name == "":
+		return nil, fmt.Errorf("rate limit rule without name")
+	case r.Limit <= 0 || r.Window <= 0:
+		return nil, fmt.Errorf("rate limit rule %q: limit and window must be positive", r.Name)
+	case r.Key != KeyIP && r.Key != KeyUser && r.Key != KeyAPIKey:
+		return nil, fmt.Errorf("rate limit rule %q: unknown key %q", r.Name, r.Key)
+	case r.Algorithm != TokenBucket && r.Algorithm != SlidingWindow:
+		return nil, fmt.Errorf("rate limit rule %q: unknown algorithm %q", r.Name, r.Algorithm)
+	}
+
+	for _, m := range cfg.Methods {
+		if strings.HasSuffix(m, "/") {
+			r.prefixes = append(r.prefixes, m)
+		} else {
+			r.methods[m] = struct{}{}
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Rule) Matches(fullMethod string) bool {
+	if _, ok := r.methods[fullMethod]; ok {
+		return true
+	}
+
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) key(id Identity) string {
+	switch {
+	case r.Key == KeyUser && id.UserID != "":
+		return fmt.Sprintf("%s:user:%s", r.Name, id.UserID)
+	case r.Key == KeyAPIKey && id.APIKey != "":
+		return fmt.Sprintf("%s:key:%s", r.Name, id.APIKey)
+	default:
+		return fmt.Sprintf("%s:ip:%s", r.Name, id.IP)
+	}
+}
+
+type Limiter struct {
+	rules       atomic.Pointer[[]*Rule]
+	apiKeys     atomic.Pointer[map[string]struct{}]
+	store       Store
+	storeName   string
+	closer      io.Closer
+	logger      *zap.Logger
+	rejected    atomic.Uint64
+	storeErrors atomic.Uint64
+}
+
+func NewLimiter(rules []*Rule, store Store, logger *zap.Logger) *Limiter {
+	l := &Limiter{
+		store:  store,
+		logger: logger,
+	}
+
+	l.rules.Store(&rules)
+	l.SetAPIKeys(nil)
+
+	return l
+}
+
+// SetRules swaps the rule set.
+func (l *Limiter) SetRules(rules []*Rule) {
+	l.rules.Store(&rules)
+}
+
+func (l *Limiter) SetAPIKeys(keys []string) {
+	hashes := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		hashes[hashAPIKey(key)] = struct{}{}
+	}
+
+	l.apiKeys.Store(&hashes)
+}
+
+// APIKey hashes configured keys; unknown keys get "" and fall back to the address.
+func (l *Limiter) APIKey(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	hash := hashAPIKey(key)
+	if _, ok := (*l.apiKeys.Load())[hash]; !ok {
+		return ""
+	}
+
+	return hash
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *Limiter) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+
+	return l.closer.Close()
+}
+
+func NewRules(cfgs []config.RateLimitRule) ([]*Rule, error) {
+	if len(cfgs) == 0 {
+		cfgs = DefaultRules
+	}
+
+	rules := make([]*Rule, 0, len(cfgs))
+
+	for _, cfg := range cfgs {
+		rule, err := NewRule(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (l *Limiter) Check(ctx context.Context, fullMethod string, id Identity) *Result {
+	var result *Result
+
+	for _, rule := range *l.rules.Load() {
+		if !rule.Matches(fullMethod) {
+			continue
+		}
+
+		var res *Result
+		var err error
+
+		switch rule.Algorithm {
+		case SlidingWindow:
+			res, err = l.store.SlidingWindow(ctx, rule.key(id), rule.Limit, rule.Window)
+		default:
+			res, err = l.store.TokenBucket(ctx, rule.key(id), rule.Limit, rule.Window, rule.Burst)
+		}
+
+		if err != nil {
+			l.storeErrors.Add(1)
+			requestid.Logger(ctx, l.logger).Warn("rate limit store error", zap.String("rule", rule.Name), zap.Error(err))
+			continue
+		}
+
+		if !res.Allowed {
+			requestid.Logger(ctx, l.logger).Debug("rate limited", zap.String("rule", rule.Name), zap.String("method", fullMethod))
+		}
+
+		result = restrictive(result, res)
+	}
+
+	if result != nil && !result.Allowed {
+		l.rejected.Add(1)
+	}
+
+	return result
+}
+
+// Status describes the limiter for the admin endpoints.
+type Status struct {
+	Store       string       `json:"store"`
+	Rules       []RuleStatus `json:"rules"`
+	Rejected    uint64       `json:"rejected"`
+	StoreErrors uint64       `json:"store_errors"`
+}
+
+type RuleStatus struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	Algorithm string   `json:"algorithm"`
+	Limit     int      `json:"limit"`
+	Window    string   `json:"window"`
+	Burst     int      `json:"burst,omitempty"`
+	Methods   []string `json:"methods,omitempty"`
+}
+
+func (l *Limiter) Snapshot() Status {
+	st := Status{
+		Store:       l.storeName,
+		Rejected:    l.rejected.Load(),
+		StoreErrors: l.storeErrors.Load(),
+	}
+
+	for _, rule := range *l.rules.Load() {
+		methods := append([]string(nil), rule.prefixes...)
+		for m := range rule.methods {
+			methods = append(methods, m)
+		}
+
+		sort.Strings(methods)
+
+		st.Rules = append(st.Rules, RuleStatus{
+			Name:      rule.Name,
+			Key:       string(rule.Key),
+			Algorithm: string(rule.Algorithm),
+			Limit:     rule.Limit,
+			Window:    rule.Window.String(),
+			Burst:     rule.Burst,
+			Methods:   methods,
+		})
+	}
+
+	return st
+}
+
+func restrictive(a, b *Result) *Result {
+	switch {
+	case a == nil:
+		return b
+	case a.Allowed != b.Allowed:
+		if a.Allowed {
+			return b
+		}
+		return a
+	case !a.Allowed:
+		if b.RetryAfter > a.RetryAfter {
+			return b
+		}
+		return a
+	case b.Remaining < a.Remaining:
+		return b
+	default:
+		return a
+	}
+}
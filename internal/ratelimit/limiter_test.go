@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+type failingStore struct{}
+
+func (failingStore) TokenBucket(context.Context, string, int, time.Duration, int) (*Result, error) {
+	return nil, errors.New("store down")
+}
+
+func (failingStore) SlidingWindow(context.Context, string, int, time.Duration) (*Result, error) {
+	return nil, errors.New("store down")
+}
+
+type recordingStore struct {
+	keys []string
+}
+
+func (s *recordingStore) TokenBucket(_ context.Context, key string, limit int, _ time.Duration, _ int) (*Result, error) {
+	s.keys = append(s.keys, key)
+	return &Result{Allowed: true, Limit: limit}, nil
+}
+
+func (s *recordingStore) SlidingWindow(_ context.Context, key string, limit int, _ time.Duration) (*Result, error) {
+	s.keys = append(s.keys, key)
+	return &Result{Allowed: true, Limit: limit}, nil
+}
+
+func testRules(t *testing.T, key KeyKind) []*Rule {
+	t.Helper()
+
+	rules, err := NewRules([]config.RateLimitRule{{
+		Name:    "test",
+		Methods: []string{"/test.v1.Service/"},
+		Key:     string(key),
+		Limit:   1,
+		Window:  time.Minute,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rules
+}
+
+func TestCheckFailsOpen(t *testing.T) {
+	limiter := NewLimiter(testRules(t, KeyIP), failingStore{}, zap.NewNop())
+
+	for range 5 {
+		if res := limiter.Check(context.Background(), "/test.v1.Service/Call", Identity{IP: "10.0.0.1"}); res != nil && !res.Allowed {
+			t.Fatal("store error rejected the call")
+		}
+	}
+
+	if got := limiter.Snapshot().StoreErrors; got != 5 {
+		t.Fatalf("store errors %d, want 5", got)
+	}
+}
+
+func TestCheckUnmatched(t *testing.T) {
+	limiter := NewLimiter(testRules(t, KeyIP), NewMemoryStore(), zap.NewNop())
+
+	if res := limiter.Check(context.Background(), "/other.v1.Service/Call", Identity{IP: "10.0.0.1"}); res != nil {
+		t.Fatalf("unmatched method limited: %+v", res)
+	}
+}
+
+func TestAPIKeyBuckets(t *testing.T) {
+	store := &recordingStore{}
+
+	limiter := NewLimiter(testRules(t, KeyAPIKey), store, zap.NewNop())
+	limiter.SetAPIKeys([]string{"known-secret"})
+
+	for _, key := range []string{"known-secret", "random-1", "random-2"} {
+		limiter.Check(context.Background(), "/test.v1.Service/Call", Identity{IP: "10.0.0.1", APIKey: limiter.APIKey(key)})
+	}
+
+	if want := "test:key:" + hashAPIKey("known-secret"); store.keys[0] != want {
+		t.Fatalf("known key bucket %q, want %q", store.keys[0], want)
+	}
+
+	for _, key := range store.keys {
+		if strings.Contains(key, "secret") || strings.Contains(key, "random") {
+			t.Fatalf("raw api key in store key %q", key)
+		}
+	}
+
+	if store.keys[1] != "test:ip:10.0.0.1" || store.keys[2] != "test:ip:10.0.0.1" {
+		t.Fatalf("unknown keys did not fall back to the address: %v", store.keys[1:])
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	ttl    time.Duration
+}
+
+type window struct {
+	start time.Time
+	prev  int
+	curr  int
+	size  time.Duration
+}
+
+type MemoryStore struct {
+	buckets map[string]*bucket
+	windows map[string]*window
+	mx      sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		windows: make(map[string]*window),
+	}
+}
+
+// Run evicts idle entries until ctx is done.
+func (s *MemoryStore) Run(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.evict(now)
+			}
+		}
+	}()
+}
+
+func (s *MemoryStore) evict(now time.Time) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for key, b := range s.buckets {
+		if now.Sub(b.last) > b.ttl {
+			delete(s.buckets, key)
+		}
+	}
+
+	for key, w := range s.windows {
+		if now.Sub(w.start) > 2*w.size {
+			delete(s.windows, key)
+		}
+	}
+}
+
+func (s *MemoryStore) TokenBucket(_ context.Context, key string, limit int, window time.Duration, burst int) (*Result, error) {
+	now := time.Now()
+	rate := float64(limit) / window.Seconds()
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now, ttl: window * 2}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return tokenBucketResult(allowed, b.tokens, limit, window, burst), nil
+}
+
+func (s *MemoryStore) SlidingWindow(_ context.Context, key string, limit int, size time.Duration) (*Result, error) {
+	now := time.Now()
+	start := now.Truncate(size)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &window{start: start, size: size}
+		s.windows[key] = w
+	}
+
+	if !w.start.Equal(start) {
+		if start.Sub(w.start) == size {
+			w.prev = w.curr
+		} else {
+			w.prev = 0
+		}
+
+		w.curr = 0
+		w.start = start
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(size)
+
+	allowed := float64(w.prev)*weight+float64(w.curr)+1 <= float64(limit)
+	if allowed {
+		w.curr++
+	}
+
+	return slidingWindowResult(allowed, w.prev, w.curr, limit, size, elapsed), nil
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/auth"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/clientip"
+)
+
+const (
+	APIKeyHeader = "X-Api-Key"
+
+	LimitHeader      = "X-RateLimit-Limit"
+	RemainingHeader  = "X-RateLimit-Remaining"
+	ResetHeader      = "X-RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+
+	RateLimitedError = "rate limit exceeded"
+)
+
+func NewMiddleware(limiter *Limiter, trustForwarded bool, errHandler runtime.ErrorHandlerFunc) runtime.Middleware {
+	marshaler := &runtime.JSONPb{}
+
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			ctx := r.Context()
+
+			id := Identity{
+				IP:     clientip.FromRequest(r, trustForwarded),
+				APIKey: limiter.APIKey(r.Header.Get(APIKeyHeader)),
+			}
+
+			if claims, ok := auth.ClaimsFromContext(ctx); ok {
+				id.UserID = claims.SubjectID()
+			}
+
+			res := limiter.Check(ctx, auth.MethodFromRequest(r), id)
+			if res == nil {
+				handlerFunc(w, r, pathParams)
+				return
+			}
+
+			header := w.Header()
+			header.Set(LimitHeader, strconv.Itoa(res.Limit))
+			header.Set(RemainingHeader, strconv.Itoa(res.Remaining))
+			header.Set(ResetHeader, ceilSeconds(res.Reset))
+
+			if !res.Allowed {
+				header.Set(RetryAfterHeader, ceilSeconds(res.RetryAfter))
+				errHandler(ctx, nil, marshaler, w, r, status.Error(codes.ResourceExhausted, RateLimitedError))
+				return
+			}
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+const (
+	MemoryStoreName = "memory"
+	RedisStoreName  = "redis"
+
+	evictInterval    = time.Minute
+	redisDialTimeout = time.Second * 10
+)
+
+// New builds a limiter backed by the store selected in cfg.
+func New(ctx context.Context, cfg *config.RateLimit, logger *zap.Logger) (*Limiter, error) {
+	rules, err := NewRules(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Store {
+	case "", MemoryStoreName:
+		store := NewMemoryStore()
+		store.Run(ctx, evictInterval)
+
+		limiter := NewLimiter(rules, store, logger)
+		limiter.storeName = MemoryStoreName
+		limiter.SetAPIKeys(cfg.APIKeys)
+
+		return limiter, nil
+	case RedisStoreName:
+		client, err := newRedisClient(ctx, cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting rate limit redis: %w", err)
+		}
+
+		limiter := NewLimiter(rules, NewRedisStore(client), logger)
+		limiter.closer = client
+		limiter.storeName = RedisStoreName
+		limiter.SetAPIKeys(cfg.APIKeys)
+
+		return limiter, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+}
+
+// newRedisClient accepts either a redis:// URL or a bare host:port address.
+func newRedisClient(ctx context.Context, url string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		opts = &redis.Options{Addr: url}
+	}
+
+	opts.DialTimeout = redisDialTimeout
+
+	client := redis.NewClient(opts)
+
+	pingCtx, cancel := context.WithTimeout(ctx, redisDialTimeout)
+	defer cancel()
+
+	if err = client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
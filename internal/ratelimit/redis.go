@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ Store = (*RedisStore)(nil)
+
+const keyPrefix = "ratelimit:"
+
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ttl)
+
+return {allowed, tostring(tokens)}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local weight = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+
+if prev * weight + curr + 1 > limit then
+	return {0, prev, curr}
+end
+
+curr = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ttl)
+
+return {1, prev, curr}
+`)
+
+// RedisStore shares limiter state between gateway replicas.
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) TokenBucket(ctx context.Context, key string, limit int, window time.Duration, burst int) (*Result, error) {
+	now := time.Now()
+	rate := float64(limit) / float64(window.Milliseconds())
+
+	res, err := tokenBucketScript.Run(ctx, s.client,
+		[]string{fmt.Sprintf("%s{%s}:tb", keyPrefix, key)},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, now.UnixMilli(), (window * 2).Milliseconds(),
+	).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) != 2 {
+		return nil, fmt.Errorf("unexpected token bucket reply: %v", res)
+	}
+
+	allowed, _ := res[0].(int64)
+	raw, _ := res[1].(string)
+
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected token bucket reply: %w", err)
+	}
+
+	return tokenBucketResult(allowed == 1, tokens, limit, window, burst), nil
+}
+
+func (s *RedisStore) SlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	now := time.Now()
+	start := now.Truncate(window)
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(window)
+
+	res, err := slidingWindowScript.Run(ctx, s.client,
+		[]string{
+			fmt.Sprintf("%s{%s}:sw:%d", keyPrefix, key, start.UnixMilli()),
+			fmt.Sprintf("%s{%s}:sw:%d", keyPrefix, key, start.Add(-window).UnixMilli()),
+		},
+		limit, strconv.FormatFloat(weight, 'f', -1, 64), (window * 2).Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) != 3 {
+		return nil, fmt.Errorf("unexpected sliding window reply: %v", res)
+	}
+
+	return slidingWindowResult(res[0] == 1, int(res[1]), int(res[2]), limit, window, elapsed), nil
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	TokenBucket   Algorithm = "token_bucket"
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Store keeps limiter state.
+type Store interface {
+	TokenBucket(ctx context.Context, key string, limit int, window time.Duration, burst int) (*Result, error)
+	SlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error)
+}
+
+func tokenBucketResult(allowed bool, tokens float64, limit int, window time.Duration, burst int) *Result {
+	rate := float64(limit) / window.Seconds()
+
+	res := &Result{
+		Allowed:   allowed,
+		Limit:     burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(burst) - tokens) / rate),
+	}
+
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+
+	return res
+}
+
+func slidingWindowResult(allowed bool, prev, curr int, limit int, window time.Duration, elapsed time.Duration) *Result {
+	weight := 1 - float64(elapsed)/float64(window)
+	estimate := float64(prev)*weight + float64(curr)
+
+	res := &Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(0, limit-int(math.Ceil(estimate))),
+		Reset:     window - elapsed,
+	}
+
+	if !allowed {
+		res.RetryAfter = slidingWindowRetry(prev, curr, limit, window, elapsed)
+	}
+
+	return res
+}
+
+func slidingWindowRetry(prev, curr int, limit int, window time.Duration, elapsed time.Duration) time.Duration {
+	if curr >= limit || prev == 0 {
+		return window - elapsed
+	}
+
+	needed := 1 - float64(limit-curr-1)/float64(prev)
+	at := time.Duration(needed * float64(window))
+
+	if at <= elapsed {
+		return time.Second
+	}
+
+	return at - elapsed
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
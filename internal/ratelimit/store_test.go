@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return map[string]Store{
+		MemoryStoreName: NewMemoryStore(),
+		RedisStoreName:  NewRedisStore(client),
+	}
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for i := range 3 {
+				res, err := store.TokenBucket(ctx, "burst", 1, time.Hour, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !res.Allowed {
+					t.Fatalf("call %d rejected within burst", i+1)
+				}
+
+				if res.Remaining != 2-i {
+					t.Fatalf("call %d: remaining %d, want %d", i+1, res.Remaining, 2-i)
+				}
+			}
+
+			res, err := store.TokenBucket(ctx, "burst", 1, time.Hour, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Allowed {
+				t.Fatal("call past burst allowed")
+			}
+
+			if res.RetryAfter <= 0 {
+				t.Fatalf("retry after %v, want positive", res.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for range 2 {
+				if _, err := store.TokenBucket(ctx, "refill", 10, time.Second, 2); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if res, _ := store.TokenBucket(ctx, "refill", 10, time.Second, 2); res.Allowed {
+				t.Fatal("empty bucket allowed a call")
+			}
+
+			time.Sleep(150 * time.Millisecond)
+
+			if res, _ := store.TokenBucket(ctx, "refill", 10, time.Second, 2); !res.Allowed {
+				t.Fatal("refilled bucket rejected a call")
+			}
+		})
+	}
+}
+
+func TestSlidingWindowRollover(t *testing.T) {
+	const size = 200 * time.Millisecond
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Start right after a boundary, so all calls land in one window.
+			time.Sleep(time.Until(time.Now().Truncate(size).Add(size)))
+
+			for i := range 3 {
+				res, err := store.SlidingWindow(ctx, "window", 3, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !res.Allowed {
+					t.Fatalf("call %d rejected within limit", i+1)
+				}
+			}
+
+			res, err := store.SlidingWindow(ctx, "window", 3, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Allowed {
+				t.Fatal("call past limit allowed")
+			}
+
+			// Two windows later the previous count no longer weighs in.
+			time.Sleep(2 * size)
+
+			res, err = store.SlidingWindow(ctx, "window", 3, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !res.Allowed || res.Remaining != 2 {
+				t.Fatalf("after rollover: allowed %v remaining %d, want true 2", res.Allowed, res.Remaining)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowKeys(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if res, _ := store.SlidingWindow(ctx, "a", 1, time.Minute); !res.Allowed {
+				t.Fatal("first call on a rejected")
+			}
+
+			if res, _ := store.SlidingWindow(ctx, "a", 1, time.Minute); res.Allowed {
+				t.Fatal("second call on a allowed")
+			}
+
+			if res, _ := store.SlidingWindow(ctx, "b", 1, time.Minute); !res.Allowed {
+				t.Fatal("first call on b rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package redact
+
+import (
+	"encoding/json"
+)
+
+func (r *Redactor) JSON(data []byte) []byte {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(`"` + Placeholder + `"`)
+	}
+
+	doc = r.walk(doc)
+
+	for _, path := range r.paths {
+		doc = redactPath(doc, path)
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return []byte(`"` + Placeholder + `"`)
+	}
+
+	return out
+}
+
+func (r *Redactor) walk(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if r.Name(key) {
+				v[key] = Placeholder
+			} else {
+				v[key] = r.walk(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = r.walk(value)
+		}
+	}
+
+	return v
+}
+
+func redactPath(v any, path []string) any {
+	if len(path) == 0 {
+		return Placeholder
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if path[0] == "*" || path[0] == key {
+				v[key] = redactPath(value, path[1:])
+			}
+		}
+	case []any:
+		if path[0] == "*" {
+			for i, value := range v {
+				v[i] = redactPath(value, path[1:])
+			}
+		}
+	}
+
+	return v
+}
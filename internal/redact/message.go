@@ -0,0 +1,74 @@
+package redact
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func (r *Redactor) Field(fd protoreflect.FieldDescriptor) bool {
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+		return true
+	}
+
+	if _, ok := r.qualified[string(fd.FullName())]; ok {
+		return true
+	}
+
+	return r.Name(string(fd.Name()))
+}
+
+// Message returns a copy of msg with every sensitive field replaced, at any depth.
+func (r *Redactor) Message(msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	r.message(msg.ProtoReflect())
+
+	return msg
+}
+
+// JSONMessage renders msg redacted, for debug dumps.
+func (r *Redactor) JSONMessage(msg proto.Message) string {
+	data, err := protojson.Marshal(r.Message(msg))
+	if err != nil {
+		return Placeholder
+	}
+
+	return string(data)
+}
+
+func (r *Redactor) message(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if r.Field(fd) {
+			switch {
+			case fd.IsList() || fd.IsMap():
+				m.Clear(fd)
+			case fd.Kind() == protoreflect.StringKind:
+				m.Set(fd, protoreflect.ValueOfString(Placeholder))
+			case fd.Kind() == protoreflect.BytesKind:
+				m.Set(fd, protoreflect.ValueOfBytes([]byte(Placeholder)))
+			default:
+				m.Clear(fd)
+			}
+
+			return true
+		}
+
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				r.message(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				r.message(mv.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			r.message(v.Message())
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,165 @@
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+const Placeholder = "[REDACTED]"
+
+var (
+	defaultFields = []string{
+		"password",
+		"old_password",
+		"new_password",
+		"token",
+		"access_token",
+		"refresh_token",
+		"id_token",
+		"secret",
+		"client_secret",
+		"usersservice.v1.OAuthLoginRequest.code",
+		"usersservice.v1.LinkOAuthProviderRequest.code",
+	}
+
+	defaultHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+	}
+)
+
+type Redactor struct {
+	names     map[string]struct{}
+	qualified map[string]struct{}
+	paths     [][]string
+	headers   map[string]struct{}
+}
+
+// New builds a redactor from the built-in lists extended by cfg.
+func New(cfg *config.Redaction) *Redactor {
+	r := &Redactor{
+		names:     make(map[string]struct{}),
+		qualified: make(map[string]struct{}),
+		headers:   make(map[string]struct{}),
+	}
+
+	for _, field := range append(defaultFields, cfg.Fields...) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if strings.Contains(field, ".") {
+			r.qualified[field] = struct{}{}
+		} else {
+			r.names[normalize(field)] = struct{}{}
+		}
+	}
+
+	for _, path := range cfg.JSONPaths {
+		path = strings.TrimPrefix(strings.TrimSpace(path), "$.")
+		if path != "" {
+			r.paths = append(r.paths, strings.Split(path, "."))
+		}
+	}
+
+	for _, header := range append(defaultHeaders, cfg.Headers...) {
+		r.headers[strings.ToLower(strings.TrimSpace(header))] = struct{}{}
+	}
+
+	return r
+}
+
+// Name reports whether a bare field, query parameter or JSON key name is sensitive.
+func (r *Redactor) Name(name string) bool {
+	_, ok := r.names[normalize(name)]
+	return ok
+}
+
+func (r *Redactor) IsHeader(name string) bool {
+	_, ok := r.headers[strings.ToLower(name)]
+	return ok
+}
+
+func (r *Redactor) Header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+
+	for key, values := range h {
+		if r.IsHeader(key) {
+			out[key] = []string{Placeholder}
+		} else {
+			out[key] = values
+		}
+	}
+
+	return out
+}
+
+func (r *Redactor) Metadata(md metadata.MD) metadata.MD {
+	out := make(metadata.MD, len(md))
+
+	for key, values := range md {
+		if r.IsHeader(key) {
+			out[key] = []string{Placeholder}
+		} else {
+			out[key] = values
+		}
+	}
+
+	return out
+}
+
+// Query encodes a query string with the values of sensitive parameters replaced.
+func (r *Redactor) Query(query string, input protoreflect.MessageDescriptor) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return Placeholder
+	}
+
+	for key := range values {
+		if r.Name(key) || input != nil && r.queryField(input, key) {
+			values[key] = []string{Placeholder}
+		}
+	}
+
+	return values.Encode()
+}
+
+// queryField resolves "a.b" parameters like the runtime mux.
+func (r *Redactor) queryField(md protoreflect.MessageDescriptor, param string) bool {
+	for _, name := range strings.Split(param, ".") {
+		if md == nil {
+			return false
+		}
+
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(name)
+		}
+
+		if fd == nil {
+			return false
+		}
+
+		if r.Field(fd) {
+			return true
+		}
+
+		md = fd.Message()
+	}
+
+	return false
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
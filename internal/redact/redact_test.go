@@ -0,0 +1,142 @@
+package redact
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+const secret = "s3cr3t-value"
+
+func sensitiveMessages() []proto.Message {
+	return []proto.Message{
+		&usersv1.RegisterRequest{Username: "alice", Email: "alice@example.com", Password: secret},
+		&usersv1.LoginRequest{Identifier: &usersv1.LoginRequest_Username{Username: "alice"}, Password: secret},
+		&usersv1.ChangePasswordRequest{UserId: "1", Password: secret},
+		&usersv1.OAuthLoginRequest{Provider: "github", Token: secret, Code: secret},
+	}
+}
+
+func TestMessage(t *testing.T) {
+	r := New(&config.Redaction{})
+
+	for _, msg := range sensitiveMessages() {
+		t.Run(string(msg.ProtoReflect().Descriptor().Name()), func(t *testing.T) {
+			out := r.JSONMessage(msg)
+
+			if strings.Contains(out, secret) {
+				t.Fatalf("secret leaked: %s", out)
+			}
+
+			if !strings.Contains(out, Placeholder) {
+				t.Fatalf("expected a placeholder: %s", out)
+			}
+
+			if !strings.Contains(fmt.Sprint(msg), secret) {
+				t.Fatal("the original message was modified")
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	r := New(&config.Redaction{JSONPaths: []string{"$.profile.code"}})
+
+	out := string(r.JSON([]byte(`{"username":"alice","newPassword":"` + secret + `",` +
+		`"sessions":[{"refresh_token":"` + secret + `"}],"profile":{"code":"` + secret + `"}}`)))
+
+	if strings.Contains(out, secret) {
+		t.Fatalf("secret leaked: %s", out)
+	}
+
+	if !strings.Contains(out, "alice") {
+		t.Fatalf("non sensitive value was redacted: %s", out)
+	}
+
+	if out = string(r.JSON([]byte(`{"password":"` + secret))); strings.Contains(out, secret) {
+		t.Fatalf("secret leaked from a malformed document: %s", out)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	r := New(&config.Redaction{})
+	oauth := (&usersv1.OAuthLoginRequest{}).ProtoReflect().Descriptor()
+
+	tests := []struct {
+		name     string
+		query    string
+		input    protoreflect.MessageDescriptor
+		redacted []string
+		kept     []string
+	}{
+		{name: "bare names", query: "password=" + secret + "&accessToken=" + secret + "&page=2", redacted: []string{"password", "accessToken"}, kept: []string{"page"}},
+		{name: "qualified name", query: "code=" + secret + "&provider=github", input: oauth, redacted: []string{"code"}, kept: []string{"provider"}},
+		{name: "qualified name without route", query: "code=abc", kept: []string{"code"}},
+		{name: "unknown parameter", query: "state=xyz", input: oauth, kept: []string{"state"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(r.Query(tt.query, tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range tt.redacted {
+				if got := values.Get(key); got != Placeholder {
+					t.Errorf("%s = %q, want it redacted", key, got)
+				}
+			}
+
+			for _, key := range tt.kept {
+				if got := values.Get(key); got == Placeholder {
+					t.Errorf("%s was redacted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestSpanExporter(t *testing.T) {
+	r := New(&config.Redaction{})
+	exporter := tracetest.NewInMemoryExporter()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(r.SpanExporter(exporter)))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	_, span := tp.Tracer("test").Start(context.Background(), "POST /v1/auth/login")
+	span.SetAttributes(
+		attribute.String("rpc.request.password", secret),
+		attribute.String("http.request.header.authorization", "Bearer "+secret),
+		attribute.String("http.route", "/v1/auth/login"),
+	)
+	span.AddEvent("message", trace.WithAttributes(attribute.String("rpc.message.token", secret)))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+
+	if out := fmt.Sprint(spans[0].Attributes, spans[0].Events); strings.Contains(out, secret) {
+		t.Fatalf("secret leaked: %s", out)
+	}
+
+	for _, kv := range spans[0].Attributes {
+		if kv.Key == "http.route" && kv.Value.AsString() != "/v1/auth/login" {
+			t.Fatalf("non sensitive attribute was redacted: %v", kv)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Attributes matches on the last segment of the key.
+func (r *Redactor) Attributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	var out []attribute.KeyValue
+
+	for i, kv := range attrs {
+		key := string(kv.Key)
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			key = key[i+1:]
+		}
+
+		if !r.Name(key) && !r.IsHeader(key) {
+			continue
+		}
+
+		if out == nil {
+			out = make([]attribute.KeyValue, len(attrs))
+			copy(out, attrs)
+		}
+
+		out[i] = attribute.String(string(kv.Key), Placeholder)
+	}
+
+	if out == nil {
+		return attrs
+	}
+
+	return out
+}
+
+var _ sdktrace.SpanExporter = (*spanExporter)(nil)
+
+type spanExporter struct {
+	next sdktrace.SpanExporter
+	r    *Redactor
+}
+
+// SpanExporter scrubs span and event attributes before next exports them.
+func (r *Redactor) SpanExporter(next sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &spanExporter{next: next, r: r}
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		redacted[i] = &redactedSpan{ReadOnlySpan: span, r: e.r}
+	}
+
+	return e.next.ExportSpans(ctx, redacted)
+}
+
+func (e *spanExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	r *Redactor
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.r.Attributes(s.ReadOnlySpan.Attributes())
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+
+	out := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		event.Attributes = s.r.Attributes(event.Attributes)
+		out[i] = event
+	}
+
+	return out
+}
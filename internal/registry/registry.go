@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+)
+
+// RegisterFunc binds the gateway handlers of a proto service to mux.
+type RegisterFunc func(ctx context.Context, mux *runtime.ServeMux, cc grpc.ClientConnInterface) error
+
+var (
+	handlers = map[string]RegisterFunc{
+		usersv1.UsersAuthService_ServiceDesc.ServiceName:    handler(usersv1.RegisterUsersAuthServiceHandlerClient, usersv1.NewUsersAuthServiceClient),
+		usersv1.UsersAdminService_ServiceDesc.ServiceName:   handler(usersv1.RegisterUsersAdminServiceHandlerClient, usersv1.NewUsersAdminServiceClient),
+		usersv1.UsersSocialService_ServiceDesc.ServiceName:  handler(usersv1.RegisterUsersSocialServiceHandlerClient, usersv1.NewUsersSocialServiceClient),
+		usersv1.UsersProfileService_ServiceDesc.ServiceName: handler(usersv1.RegisterUsersProfileServiceHandlerClient, usersv1.NewUsersProfileServiceClient),
+
+		questionsv1.QuestionsService_ServiceDesc.ServiceName:       handler(questionsv1.RegisterQuestionsServiceHandlerClient, questionsv1.NewQuestionsServiceClient),
+		questionsv1.QuestionsAdminService_ServiceDesc.ServiceName:  handler(questionsv1.RegisterQuestionsAdminServiceHandlerClient, questionsv1.NewQuestionsAdminServiceClient),
+		questionsv1.QuestionsClientService_ServiceDesc.ServiceName: handler(questionsv1.RegisterQuestionsClientServiceHandlerClient, questionsv1.NewQuestionsClientServiceClient),
+	}
+	mx sync.RWMutex
+)
+
+func handler[C any](register func(context.Context, *runtime.ServeMux, C) error, newClient func(grpc.ClientConnInterface) C) RegisterFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, cc grpc.ClientConnInterface) error {
+		return register(ctx, mux, newClient(cc))
+	}
+}
+
+// Register adds the generated gateway handler of a proto service.
+func Register(service string, fn RegisterFunc) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	handlers[service] = fn
+}
+
+func Lookup(service string) (RegisterFunc, bool) {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	fn, ok := handlers[service]
+	return fn, ok
+}
+
+// Expand resolves a configured service entry into registered service names.
+func Expand(entry string) []string {
+	prefix, wildcard := strings.CutSuffix(entry, "*")
+	if !wildcard {
+		return []string{entry}
+	}
+
+	mx.RLock()
+	defer mx.RUnlock()
+
+	var names []string
+	for name := range handlers {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func Services() []string {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
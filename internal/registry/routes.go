@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	routes = map[string]string{}
+
+	bareVariable = regexp.MustCompile(`\{([^}=]+)\}`)
+)
+
+func AddRoute(httpMethod, template, fullMethod string) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	routes[routeKey(httpMethod, template)] = fullMethod
+}
+
+func MethodForRoute(httpMethod, pattern string) (string, bool) {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	fullMethod, ok := routes[routeKey(httpMethod, pattern)]
+	return fullMethod, ok
+}
+
+// routeKey spells templates the way runtime.Pattern prints them.
+func routeKey(httpMethod, template string) string {
+	return strings.ToUpper(httpMethod) + " " + bareVariable.ReplaceAllString(template, "{$1=*}")
+}
@@ -0,0 +1,53 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(incoming(ctx), req)
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpcmiddleware.WrapServerStream(ss)
+		wrapped.WrappedContext = incoming(ss.Context())
+
+		return handler(srv, wrapped)
+	}
+}
+
+// Bridged calls carry the request ID of the HTTP layer already.
+func incoming(ctx context.Context) context.Context {
+	if id := FromContext(ctx); id != "" {
+		return ctx
+	}
+
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataKey); len(values) > 0 && valid(values[0]) {
+		id = values[0]
+	} else {
+		id = uuid.NewString()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+
+	ctx = NewContext(ctx, id)
+	annotate(ctx, id)
+
+	return ctx
+}
+
+// Outgoing sets the request ID of ctx on the metadata sent upstream.
+func Outgoing(ctx context.Context, md metadata.MD) {
+	if id := FromContext(ctx); id != "" {
+		md.Set(MetadataKey, id)
+	}
+}
@@ -0,0 +1,85 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+type idKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// Logger returns logger with the request ID of ctx attached, when there is one.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id := FromContext(ctx); id != "" {
+		return logger.With(zap.String("request_id", id))
+	}
+
+	return logger
+}
+
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(Header, id)
+
+		ctx := NewContext(r.Context(), id)
+		annotate(ctx, id)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Metadata is a runtime.WithMetadata annotator forwarding the request ID upstream.
+func Metadata(ctx context.Context, _ *http.Request) metadata.MD {
+	if id := FromContext(ctx); id != "" {
+		return metadata.Pairs(MetadataKey, id)
+	}
+
+	return nil
+}
+
+func annotate(ctx context.Context, id string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/registry"
+)
+
+func Handle(mux *runtime.ServeMux, cc grpc.ClientConnInterface, binding Binding) error {
+	md, err := registry.FindMethod(binding.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	input, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return fmt.Errorf("%s: %w", binding.FullMethod, err)
+	}
+
+	output, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return fmt.Errorf("%s: %w", binding.FullMethod, err)
+	}
+
+	var bodyField protoreflect.FieldDescriptor
+	if binding.Body != "" && binding.Body != "*" {
+		bodyField = md.Input().Fields().ByName(protoreflect.Name(binding.Body))
+		if bodyField == nil || bodyField.Message() == nil {
+			return fmt.Errorf("%s: body %q is not a message field", binding.FullMethod, binding.Body)
+		}
+	}
+
+	h := func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, binding.FullMethod, runtime.WithHTTPPathPattern(binding.Template))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+
+		protoReq := input.New().Interface()
+
+		if err = populate(protoReq, req, pathParams, inboundMarshaler, binding.Body, bodyField); err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+
+		var metadata runtime.ServerMetadata
+		resp := output.New().Interface()
+
+		err = cc.Invoke(annotatedContext, binding.FullMethod, protoReq, resp, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, metadata)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+
+		runtime.ForwardResponseMessage(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	}
+
+	if err = mux.HandlePath(binding.HTTPMethod, binding.Template, h); err != nil {
+		return fmt.Errorf("%s %s: %w", binding.HTTPMethod, binding.Template, err)
+	}
+
+	registry.AddRoute(binding.HTTPMethod, binding.Template, binding.FullMethod)
+
+	return nil
+}
+
+func populate(msg proto.Message, req *http.Request, pathParams map[string]string, marshaler runtime.Marshaler, body string, bodyField protoreflect.FieldDescriptor) error {
+	switch {
+	case body == "*":
+		if err := marshaler.NewDecoder(req.Body).Decode(msg); err != nil && !errors.Is(err, io.EOF) {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	case bodyField != nil:
+		target := msg.ProtoReflect().Mutable(bodyField).Message().Interface()
+		if err := marshaler.NewDecoder(req.Body).Decode(target); err != nil && !errors.Is(err, io.EOF) {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	bound := make([][]string, 0, len(pathParams)+1)
+
+	for param, value := range pathParams {
+		if err := runtime.PopulateFieldFromPath(msg, param, value); err != nil {
+			return status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", param, err)
+		}
+
+		bound = append(bound, strings.Split(param, "."))
+	}
+
+	if body == "*" {
+		return nil
+	}
+
+	if bodyField != nil {
+		bound = append(bound, []string{string(bodyField.Name())})
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := runtime.PopulateQueryParameters(msg, req.Form, utilities.NewDoubleArray(bound)); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed rules.yaml
+var defaultRules []byte
+
+// Rule follows google.api.HttpRule.
+type Rule struct {
+	Selector           string `mapstructure:"selector"`
+	Get                string `mapstructure:"get"`
+	Put                string `mapstructure:"put"`
+	Post               string `mapstructure:"post"`
+	Delete             string `mapstructure:"delete"`
+	Patch              string `mapstructure:"patch"`
+	Body               string `mapstructure:"body"`
+	AdditionalBindings []Rule `mapstructure:"additional_bindings"`
+}
+
+type document struct {
+	HTTP struct {
+		Rules []Rule `mapstructure:"rules"`
+	} `mapstructure:"http"`
+}
+
+// Binding is a single resolved route of a rule.
+type Binding struct {
+	FullMethod string
+	HTTPMethod string
+	Template   string
+	Body       string
+}
+
+// LoadRules reads the rule file at path, or the built-in rules when path is empty.
+func LoadRules(path string) ([]Binding, error) {
+	data := defaultRules
+
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("error reading http rules: %w", err)
+		}
+	}
+
+	return ParseRules(data)
+}
+
+func ParseRules(data []byte) ([]Binding, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error reading http rules: %w", err)
+	}
+
+	var doc document
+	if err := v.Unmarshal(&doc); err != nil {
+		return nil, fmt.Errorf("error decoding http rules: %w", err)
+	}
+
+	var bindings []Binding
+
+	for _, rule := range doc.HTTP.Rules {
+		fullMethod, err := fullMethodName(rule.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range append([]Rule{rule}, rule.AdditionalBindings...) {
+			binding, err := bind(fullMethod, r)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.Selector, err)
+			}
+
+			bindings = append(bindings, binding)
+		}
+	}
+
+	return bindings, nil
+}
+
+func bind(fullMethod string, r Rule) (Binding, error) {
+	binding := Binding{FullMethod: fullMethod, Body: r.Body}
+
+	for method, template := range map[string]string{
+		http.MethodGet:    r.Get,
+		http.MethodPut:    r.Put,
+		http.MethodPost:   r.Post,
+		http.MethodDelete: r.Delete,
+		http.MethodPatch:  r.Patch,
+	} {
+		if template == "" {
+			continue
+		}
+
+		if binding.Template != "" {
+			return Binding{}, fmt.Errorf("more than one pattern in a binding")
+		}
+
+		binding.HTTPMethod = method
+		binding.Template = template
+	}
+
+	if binding.Template == "" {
+		return Binding{}, fmt.Errorf("binding without pattern")
+	}
+
+	return binding, nil
+}
+
+// fullMethodName turns a "pkg.Service.Method" selector into "/pkg.Service/Method".
+func fullMethodName(selector string) (string, error) {
+	i := strings.LastIndex(selector, ".")
+	if i <= 0 || i == len(selector)-1 {
+		return "", fmt.Errorf("invalid selector %q", selector)
+	}
+
+	return "/" + selector[:i] + "/" + selector[i+1:], nil
+}
@@ -0,0 +1,154 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/grpccode"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/requestid"
+)
+
+// grpc-go does not implement hedging policies, so the gateway does.
+type Hedge struct {
+	MaxAttempts int
+	Delay       time.Duration
+	nonFatal    map[codes.Code]struct{}
+}
+
+func newHedge(cfg config.RetryPolicy) *Hedge {
+	h := &Hedge{
+		MaxAttempts: cfg.MaxAttempts,
+		Delay:       cfg.HedgingDelay,
+		nonFatal:    make(map[codes.Code]struct{}, len(cfg.NonFatalCodes)),
+	}
+
+	for _, name := range cfg.NonFatalCodes {
+		code, _ := grpccode.Parse(name)
+		h.nonFatal[code] = struct{}{}
+	}
+
+	return h
+}
+
+type attempt struct {
+	reply   proto.Message
+	header  metadata.MD
+	trailer metadata.MD
+	err     error
+}
+
+// UnaryClientInterceptor hedges the methods in hedges and passes every other call through.
+func UnaryClientInterceptor(hedges map[string]*Hedge, logger *zap.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		hedge, ok := hedges[method]
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		msg, ok := reply.(proto.Message)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		return hedge.invoke(ctx, method, req, msg, cc, invoker, requestid.Logger(ctx, logger), opts...)
+	}
+}
+
+func (h *Hedge) invoke(ctx context.Context, method string, req any, reply proto.Message, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, logger *zap.Logger, opts ...grpc.CallOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Header and trailer destinations are per attempt.
+	var header, trailer *metadata.MD
+	rest := make([]grpc.CallOption, 0, len(opts))
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			header = o.HeaderAddr
+		case grpc.TrailerCallOption:
+			trailer = o.TrailerAddr
+		default:
+			rest = append(rest, opt)
+		}
+	}
+
+	results := make(chan *attempt, h.MaxAttempts)
+
+	launch := func() {
+		a := &attempt{reply: reply.ProtoReflect().New().Interface()}
+
+		go func() {
+			attemptOpts := append(rest[:len(rest):len(rest)], grpc.Header(&a.header), grpc.Trailer(&a.trailer))
+			a.err = invoker(ctx, method, req, a.reply, cc, attemptOpts...)
+			results <- a
+		}()
+	}
+
+	finish := func(a *attempt) error {
+		if header != nil {
+			*header = a.header
+		}
+
+		if trailer != nil {
+			*trailer = a.trailer
+		}
+
+		if a.err == nil {
+			proto.Merge(reply, a.reply)
+		}
+
+		return a.err
+	}
+
+	timer := time.NewTimer(h.Delay)
+	defer timer.Stop()
+
+	launch()
+	launched, pending := 1, 1
+
+	var last *attempt
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if launched < h.MaxAttempts {
+				logger.Debug("hedging call", zap.String("method", method), zap.Int("attempt", launched+1))
+				launch()
+				launched++
+				pending++
+				timer.Reset(h.Delay)
+			}
+		case a := <-results:
+			pending--
+			last = a
+
+			if a.err == nil {
+				return finish(a)
+			}
+
+			if _, ok := h.nonFatal[status.Code(a.err)]; !ok {
+				return finish(a)
+			}
+
+			// A non-fatal failure pushes the next hedge out right away.
+			if launched < h.MaxAttempts {
+				logger.Debug("hedging call", zap.String("method", method), zap.Int("attempt", launched+1))
+				launch()
+				launched++
+				pending++
+				timer.Reset(h.Delay)
+			}
+		}
+	}
+
+	return finish(last)
+}
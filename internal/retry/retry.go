@@ -0,0 +1,348 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
+	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/grpccode"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/lb"
+)
+
+var DefaultPolicy = config.RetryPolicy{
+	MaxAttempts:       3,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        time.Second,
+	BackoffMultiplier: 2,
+	RetryableCodes:    []string{codes.Unavailable.String()},
+}
+
+// The protos do not set idempotency_level.
+var DefaultIdempotentMethods = []string{
+	questionsv1.QuestionsAdminService_GetFilteredQuestions_FullMethodName,
+	questionsv1.QuestionsClientService_GetCategories_FullMethodName,
+	questionsv1.QuestionsService_GetQuestions_FullMethodName,
+	questionsv1.QuestionsService_GetQuestionBatch_FullMethodName,
+	usersv1.UsersAdminService_SearchUsers_FullMethodName,
+	usersv1.UsersAdminService_GetUserByIdentifier_FullMethodName,
+	usersv1.UsersProfileService_GetProfile_FullMethodName,
+	usersv1.UsersSocialService_ListFriends_FullMethodName,
+}
+
+func Idempotent(methods []string, fullMethod string, md protoreflect.MethodDescriptor) bool {
+	for _, m := range methods {
+		if m == fullMethod || strings.HasSuffix(m, "/") && strings.HasPrefix(fullMethod, m) {
+			return true
+		}
+	}
+
+	if md == nil {
+		return false
+	}
+
+	opts, ok := md.Options().(*descriptorpb.MethodOptions)
+	return ok && opts.GetIdempotencyLevel() != descriptorpb.MethodOptions_IDEMPOTENCY_UNKNOWN
+}
+
+type policy struct {
+	cfg      config.RetryPolicy
+	methods  map[string]struct{}
+	prefixes []string
+	names    []methodName
+}
+
+func (p *policy) match(fullMethod string) int {
+	if len(p.cfg.Methods) == 0 {
+		return 1
+	}
+
+	if _, ok := p.methods[fullMethod]; ok {
+		return len(fullMethod) + 2
+	}
+
+	var best int
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(fullMethod, prefix) && len(prefix)+1 > best {
+			best = len(prefix) + 1
+		}
+	}
+
+	return best
+}
+
+type Config struct {
+	ServiceConfig string
+	MaxAttempts   int
+	Hedges        map[string]*Hedge
+	Skipped       []string
+}
+
+func Build(services []string, policies []config.RetryPolicy, idempotent []string) (*Config, error) {
+	if len(policies) == 0 {
+		policies = []config.RetryPolicy{DefaultPolicy}
+	}
+
+	if len(idempotent) == 0 {
+		idempotent = DefaultIdempotentMethods
+	}
+
+	compiled := make([]*policy, 0, len(policies))
+
+	for i, cfg := range policies {
+		if err := validate(cfg); err != nil {
+			return nil, fmt.Errorf("retry policy %d: %w", i, err)
+		}
+
+		p := &policy{cfg: cfg, methods: make(map[string]struct{})}
+		for _, m := range cfg.Methods {
+			if strings.HasSuffix(m, "/") {
+				p.prefixes = append(p.prefixes, m)
+			} else {
+				p.methods[m] = struct{}{}
+			}
+		}
+
+		compiled = append(compiled, p)
+	}
+
+	result := &Config{Hedges: make(map[string]*Hedge)}
+	known := make(map[string]struct{})
+
+	for _, sd := range resolveServices(services) {
+		methods := sd.Methods()
+
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			fullMethod := fmt.Sprintf("/%s/%s", sd.FullName(), md.Name())
+			known[fullMethod] = struct{}{}
+
+			p := bestPolicy(compiled, fullMethod)
+			if p == nil {
+				continue
+			}
+
+			if !p.cfg.Idempotent && !Idempotent(idempotent, fullMethod, md) {
+				result.Skipped = append(result.Skipped, fullMethod)
+				continue
+			}
+
+			p.names = append(p.names, methodName{Service: string(sd.FullName()), Method: string(md.Name())})
+		}
+	}
+
+	// Methods of unknown services are only covered when marked safe.
+	for _, p := range compiled {
+		if !p.cfg.Idempotent {
+			continue
+		}
+
+		for m := range p.methods {
+			if _, ok := known[m]; ok {
+				continue
+			}
+
+			service, method, ok := strings.Cut(strings.TrimPrefix(m, "/"), "/")
+			if ok {
+				p.names = append(p.names, methodName{Service: service, Method: method})
+			}
+		}
+	}
+
+	sc := serviceConfig{LoadBalancingPolicy: lb.Name}
+
+	for _, p := range compiled {
+		if len(p.names) == 0 {
+			continue
+		}
+
+		sort.Slice(p.names, func(i, j int) bool {
+			return p.names[i].Service+"/"+p.names[i].Method < p.names[j].Service+"/"+p.names[j].Method
+		})
+
+		if p.cfg.HedgingDelay > 0 {
+			hedge := newHedge(p.cfg)
+			for _, name := range p.names {
+				result.Hedges[fmt.Sprintf("/%s/%s", name.Service, name.Method)] = hedge
+			}
+
+			continue
+		}
+
+		result.MaxAttempts = max(result.MaxAttempts, p.cfg.MaxAttempts)
+
+		sc.MethodConfig = append(sc.MethodConfig, methodConfig{
+			Name: p.names,
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          p.cfg.MaxAttempts,
+				InitialBackoff:       duration(p.cfg.InitialBackoff),
+				MaxBackoff:           duration(p.cfg.MaxBackoff),
+				BackoffMultiplier:    p.cfg.BackoffMultiplier,
+				RetryableStatusCodes: codeNames(p.cfg.RetryableCodes),
+			},
+		})
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding service config: %w", err)
+	}
+
+	result.ServiceConfig = string(data)
+
+	return result, nil
+}
+
+func validate(cfg config.RetryPolicy) error {
+	if cfg.MaxAttempts < 2 {
+		return fmt.Errorf("max attempts must be at least 2")
+	}
+
+	if cfg.HedgingDelay > 0 {
+		return validCodes(cfg.NonFatalCodes)
+	}
+
+	switch {
+	case cfg.InitialBackoff <= 0 || cfg.MaxBackoff <= 0:
+		return fmt.Errorf("backoff must be positive")
+	case cfg.BackoffMultiplier <= 0:
+		return fmt.Errorf("backoff multiplier must be positive")
+	case len(cfg.RetryableCodes) == 0:
+		return fmt.Errorf("retryable codes are required")
+	}
+
+	return validCodes(cfg.RetryableCodes)
+}
+
+func validCodes(names []string) error {
+	for _, name := range names {
+		if _, ok := grpccode.Parse(name); !ok {
+			return fmt.Errorf("unknown status code %q", name)
+		}
+	}
+
+	return nil
+}
+
+func bestPolicy(policies []*policy, fullMethod string) *policy {
+	var best *policy
+	var rank int
+
+	for _, p := range policies {
+		if r := p.match(fullMethod); r > rank {
+			best, rank = p, r
+		}
+	}
+
+	return best
+}
+
+func resolveServices(entries []string) []protoreflect.ServiceDescriptor {
+	var services []protoreflect.ServiceDescriptor
+	seen := make(map[protoreflect.FullName]struct{})
+
+	add := func(sd protoreflect.ServiceDescriptor) {
+		if _, ok := seen[sd.FullName()]; !ok {
+			seen[sd.FullName()] = struct{}{}
+			services = append(services, sd)
+		}
+	}
+
+	for _, entry := range entries {
+		prefix, wildcard := strings.CutSuffix(entry, "*")
+		if !wildcard {
+			if d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(entry)); err == nil {
+				if sd, ok := d.(protoreflect.ServiceDescriptor); ok {
+					add(sd)
+				}
+			}
+
+			continue
+		}
+
+		protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			for i := 0; i < fd.Services().Len(); i++ {
+				if sd := fd.Services().Get(i); strings.HasPrefix(string(sd.FullName()), prefix) {
+					add(sd)
+				}
+			}
+
+			return true
+		})
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].FullName() < services[j].FullName()
+	})
+
+	return services
+}
+
+type serviceConfig struct {
+	LoadBalancingPolicy string         `json:"loadBalancingPolicy"`
+	MethodConfig        []methodConfig `json:"methodConfig,omitempty"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+func duration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// codeNames renders status codes the way service configs spell them ("DEADLINE_EXCEEDED").
+func codeNames(names []string) []string {
+	out := make([]string, 0, len(names))
+
+	for _, name := range names {
+		code, _ := grpccode.Parse(name)
+
+		switch code {
+		case codes.OK:
+			out = append(out, "OK")
+			continue
+		case codes.Canceled:
+			out = append(out, "CANCELLED")
+			continue
+		}
+
+		var b strings.Builder
+		for i, r := range code.String() {
+			if i > 0 && unicode.IsUpper(r) {
+				b.WriteByte('_')
+			}
+
+			b.WriteRune(unicode.ToUpper(r))
+		}
+
+		out = append(out, b.String())
+	}
+
+	return out
+}
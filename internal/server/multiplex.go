@@ -0,0 +1,27 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+func multiplex(grpcHandler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && isGRPC(r.Header.Get("Content-Type")) {
+			grpcHandler.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isGRPC(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/grpc" || strings.HasPrefix(mediaType, "application/grpc+")
+}
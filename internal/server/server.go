@@ -2,22 +2,24 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/QuizWars-Ecosystem/api-gateway/internal/mux"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/DavidMovas/gopherbox/pkg/closer"
-	questionsv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/questions/v1"
-	usersv1 "github.com/QuizWars-Ecosystem/api-gateway/gen/external/users/v1"
 	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/drain"
 	"github.com/QuizWars-Ecosystem/api-gateway/internal/gateway"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/logging"
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/tlsconfig"
 	"github.com/QuizWars-Ecosystem/go-common/pkg/abstractions"
-	"github.com/QuizWars-Ecosystem/go-common/pkg/log"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -25,45 +27,45 @@ import (
 var _ abstractions.Server = (*Server)(nil)
 
 type Server struct {
-	gateway *gateway.Gateway
-	logger  *log.Logger
-	cfg     *config.Config
-	closer  *closer.Closer
+	gateway  *gateway.Gateway
+	reloader *config.Reloader
+	logger   *logging.Logger
+	cfg      *config.Config
+	httpTLS  *tls.Config
+	httpSrv  *http.Server
+	grpcSrv  *grpc.Server
+	closer   *closer.Closer
 }
 
 func NewServer(_ context.Context, cfg *config.Config) (*Server, error) {
 	cl := closer.NewCloser()
 
-	logger := log.NewLogger(cfg.Local, cfg.LogLevel)
+	logger := logging.NewLogger(cfg.Local, cfg.LogLevel)
 	cl.PushIO(logger)
 
-	srvOpts := []*gateway.ServiceOption{
-		{
-			Address: "users-service",
-			RegisterFunc: []func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error{
-				usersv1.RegisterUsersAuthServiceHandler,
-				usersv1.RegisterUsersAdminServiceHandler,
-				usersv1.RegisterUsersSocialServiceHandler,
-				usersv1.RegisterUsersProfileServiceHandler,
-			},
-		},
-		{
-			Address: "questions-service",
-			RegisterFunc: []func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error{
-				questionsv1.RegisterQuestionsServiceHandler,
-				questionsv1.RegisterQuestionsAdminServiceHandler,
-				questionsv1.RegisterQuestionsClientServiceHandler,
-			},
-		},
+	var httpTLS *tls.Config
+
+	if cfg.HTTPTLS.Enabled {
+		var err error
+		if httpTLS, err = tlsconfig.NewServerConfig(&cfg.HTTPTLS); err != nil {
+			logger.Zap().Error("error initializing http tls", zap.Error(err))
+			return nil, err
+		}
 	}
 
-	gt, err := gateway.NewGateway(cfg.ConsulURL, srvOpts, logger)
+	srvOpts, err := gateway.NewServiceOptions(cfg.Upstreams, logger.Zap())
+	if err != nil {
+		logger.Zap().Error("error building upstream services", zap.Error(err))
+		return nil, err
+	}
+
+	gt, err := gateway.NewGateway(cfg, srvOpts, logger)
 	if err != nil {
 		logger.Zap().Error("error initializing gateway", zap.Error(err))
 		return nil, err
 	}
 
-	err = mux.RegisterRuntimeMux(gt.ServeMux())
+	err = mux.RegisterRuntimeMux(gt.ServeMux(), gt.Health())
 	if err != nil {
 		logger.Zap().Error("error registering runtime mux", zap.Error(err))
 		return nil, err
@@ -71,13 +73,41 @@ func NewServer(_ context.Context, cfg *config.Config) (*Server, error) {
 
 	if cfg.Local {
 		reflection.Register(gt.Proxy())
+		reflection.Register(gt.HandlerProxy())
+	}
+
+	reloader := config.NewReloader(cfg, logger)
+	reloader.Subscribe(gt)
+
+	handler := gt.Handler()
+	if cfg.SinglePort {
+		handler = multiplex(gt.HandlerProxy(), handler)
+	}
+
+	httpSrv := &http.Server{Handler: handler, TLSConfig: httpTLS}
+
+	var grpcSrv *grpc.Server
+
+	if cfg.SinglePort {
+		// gRPC clients speak HTTP/2 with prior knowledge, in plaintext inside the cluster.
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		httpSrv.Protocols = &protocols
+	} else {
+		grpcSrv = gt.Proxy()
 	}
 
 	return &Server{
-		gateway: gt,
-		logger:  logger,
-		cfg:     cfg,
-		closer:  cl,
+		gateway:  gt,
+		reloader: reloader,
+		logger:   logger,
+		cfg:      cfg,
+		httpTLS:  httpTLS,
+		httpSrv:  httpSrv,
+		grpcSrv:  grpcSrv,
+		closer:   cl,
 	}, nil
 }
 
@@ -91,19 +121,27 @@ func (s *Server) Start() error {
 		return err
 	}
 
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+	s.closer.PushNE(watchCancel)
+
+	if err := s.reloader.Watch(watchCtx); err != nil {
+		logger.Error("error watching config", zap.Error(err))
+		return err
+	}
+
 	group := errgroup.Group{}
 
 	group.Go(func() error {
-		logger.Info("starting http runtime server", zap.String("port", httpPort))
+		logger.Info("starting http runtime server", zap.String("port", httpPort), zap.Bool("single_port", s.cfg.SinglePort))
 
 		if ls, err := net.Listen("tcp", fmt.Sprintf(":%s", httpPort)); err == nil {
-			serveMux := s.gateway.ServeMux()
-			tcpSrv := &http.Server{Handler: serveMux}
-
-			s.closer.PushIO(ls)
-			s.closer.PushIO(tcpSrv)
+			if s.httpTLS != nil {
+				err = s.httpSrv.ServeTLS(ls, "", "")
+			} else {
+				err = s.httpSrv.Serve(ls)
+			}
 
-			if err = tcpSrv.Serve(ls); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
 				logger.Error("error serving http serveMux server", zap.Error(err))
 				return err
 			}
@@ -117,16 +155,15 @@ func (s *Server) Start() error {
 		}
 	})
 
+	if s.cfg.SinglePort {
+		return group.Wait()
+	}
+
 	group.Go(func() error {
 		logger.Info("starting grpc proxy server", zap.String("port", grpcPort))
 
 		if ls, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort)); err == nil {
-			proxy := s.gateway.Proxy()
-
-			s.closer.PushIO(ls)
-			s.closer.PushNE(proxy.GracefulStop)
-
-			if err = proxy.Serve(ls); err != nil {
+			if err = s.grpcSrv.Serve(ls); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 				logger.Error("error serving grpc proxy server", zap.Error(err))
 				return err
 			}
@@ -140,12 +177,77 @@ func (s *Server) Start() error {
 		}
 	})
 
-	s.closer.Push(s.gateway.Stop)
-
 	return group.Wait()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Zap().Info("Shutting down server...")
+
+	s.drain(ctx)
+
+	if err := s.gateway.Stop(); err != nil {
+		s.logger.Zap().Error("error stopping gateway", zap.Error(err))
+	}
+
 	return s.closer.Close(ctx)
 }
+
+func (s *Server) drain(ctx context.Context) {
+	logger := s.logger.Zap()
+
+	s.gateway.Drain()
+
+	select {
+	case <-time.After(s.cfg.Drain.Delay):
+	case <-ctx.Done():
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, s.cfg.Drain.Timeout)
+	defer cancel()
+
+	tracker := s.gateway.Tracker()
+
+	logger.Info("waiting for in-flight calls",
+		zap.Int64("http", tracker.InFlight(drain.ProtocolHTTP)),
+		zap.Int64("grpc", tracker.InFlight(drain.ProtocolGRPC)),
+		zap.Duration("timeout", s.cfg.Drain.Timeout),
+	)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if err := s.httpSrv.Shutdown(drainCtx); err != nil {
+			logger.Warn("http calls cut off", zap.Int64("calls", tracker.Cut(drain.ProtocolHTTP)), zap.Error(err))
+			_ = s.httpSrv.Close()
+		}
+	}()
+
+	if s.grpcSrv != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			stopped := make(chan struct{})
+			go func() {
+				s.grpcSrv.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-drainCtx.Done():
+				logger.Warn("grpc calls cut off", zap.Int64("calls", tracker.Cut(drain.ProtocolGRPC)), zap.Error(drainCtx.Err()))
+				s.grpcSrv.Stop()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	logger.Info("server drained")
+}
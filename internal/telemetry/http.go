@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untraced are the probe and scrape endpoints, which would only add noise.
+var untraced = map[string]struct{}{
+	"/livez":   {},
+	"/readyz":  {},
+	"/healthz": {},
+	"/metrics": {},
+}
+
+// Handler starts a server span for every request, continuing the trace of the caller.
+func Handler(next http.Handler, tp trace.TracerProvider) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithTracerProvider(tp),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			_, ok := untraced[r.URL.Path]
+			return !ok
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+}
+
+// Middleware names the span after the matched route.
+func Middleware() runtime.Middleware {
+	return func(handlerFunc runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + pattern.String())
+				span.SetAttributes(semconv.HTTPRoute(pattern.String()))
+			}
+
+			handlerFunc(w, r, pathParams)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tlsconfig
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertPool serves the CA bundle of a file and reloads it once the file changes.
+type CertPool struct {
+	path string
+
+	mx    sync.Mutex
+	pool  *x509.CertPool
+	files files
+}
+
+func NewCertPool(path string) (*CertPool, error) {
+	cp := &CertPool{path: path, files: files{names: []string{path}}}
+
+	modTime, err := cp.files.lastModified()
+	if err != nil {
+		return nil, fmt.Errorf("error reading ca file: %w", err)
+	}
+
+	if err = cp.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func (cp *CertPool) Pool() *x509.CertPool {
+	cp.mx.Lock()
+	defer cp.mx.Unlock()
+
+	if modTime, ok := cp.files.changed(time.Now()); ok {
+		_ = cp.load(modTime)
+	}
+
+	return cp.pool
+}
+
+func (cp *CertPool) load(modTime time.Time) error {
+	pool, err := LoadCertPool(cp.path)
+	if err != nil {
+		return err
+	}
+
+	cp.pool = pool
+	cp.files.loaded(modTime)
+
+	return nil
+}
+
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ca file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return pool, nil
+}
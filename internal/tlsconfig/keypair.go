@@ -0,0 +1,116 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkInterval bounds how often the files are looked at for changes.
+var checkInterval = 10 * time.Second
+
+type KeyPair struct {
+	certFile string
+	keyFile  string
+
+	mx    sync.Mutex
+	cert  *tls.Certificate
+	files files
+}
+
+func NewKeyPair(certFile, keyFile string) (*KeyPair, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("cert and key files are required")
+	}
+
+	kp := &KeyPair{certFile: certFile, keyFile: keyFile, files: files{names: []string{certFile, keyFile}}}
+
+	modTime, err := kp.files.lastModified()
+	if err != nil {
+		return nil, fmt.Errorf("error reading key pair: %w", err)
+	}
+
+	if err = kp.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return kp, nil
+}
+
+func (kp *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return kp.certificate(), nil
+}
+
+func (kp *KeyPair) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return kp.certificate(), nil
+}
+
+func (kp *KeyPair) certificate() *tls.Certificate {
+	kp.mx.Lock()
+	defer kp.mx.Unlock()
+
+	if modTime, ok := kp.files.changed(time.Now()); ok {
+		_ = kp.load(modTime)
+	}
+
+	return kp.cert
+}
+
+func (kp *KeyPair) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading key pair: %w", err)
+	}
+
+	kp.cert = &cert
+	kp.files.loaded(modTime)
+
+	return nil
+}
+
+// files tracks the latest modification of a set of files.
+type files struct {
+	names     []string
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func (f *files) changed(now time.Time) (time.Time, bool) {
+	if now.Sub(f.checkedAt) < checkInterval {
+		return time.Time{}, false
+	}
+
+	f.checkedAt = now
+
+	modTime, err := f.lastModified()
+	if err != nil || modTime.Equal(f.modTime) {
+		return time.Time{}, false
+	}
+
+	return modTime, true
+}
+
+func (f *files) loaded(modTime time.Time) {
+	f.modTime = modTime
+	f.checkedAt = time.Now()
+}
+
+// lastModified is the latest modification of any file.
+func (f *files) lastModified() (time.Time, error) {
+	var latest time.Time
+
+	for _, name := range f.names {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
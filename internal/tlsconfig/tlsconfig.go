@@ -0,0 +1,157 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// NewServerConfig builds the TLS config of a listener.
+func NewServerConfig(cfg *config.TLS) (*tls.Config, error) {
+	kp, err := NewKeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	minVersion, err := parseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	suites, err := parseCipherSuites(cfg.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, ok := clientAuthTypes[strings.ToLower(cfg.ClientAuth)]
+	if cfg.ClientAuth == "" {
+		clientAuth, ok = tls.NoClientCert, true
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown client auth %q", cfg.ClientAuth)
+	}
+
+	tlsCfg := &tls.Config{
+		GetCertificate: kp.GetCertificate,
+		MinVersion:     minVersion,
+		CipherSuites:   suites,
+		ClientAuth:     clientAuth,
+	}
+
+	if cfg.ClientCAFile != "" {
+		clientCAs, err := NewCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsCfg.ClientCAs = clientCAs.Pool()
+
+		base := tlsCfg.Clone()
+		tlsCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := base.Clone()
+			c.ClientCAs = clientCAs.Pool()
+
+			return c, nil
+		}
+	} else if clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert {
+		return nil, fmt.Errorf("client auth %s requires a client ca file", cfg.ClientAuth)
+	}
+
+	return tlsCfg, nil
+}
+
+// NewClientConfig builds the TLS config of an upstream connection.
+func NewClientConfig(cfg *config.UpstreamTLS) (*tls.Config, error) {
+	minVersion, err := parseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         minVersion,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicitly opted in through configuration
+	}
+
+	if cfg.CAFile != "" {
+		if tlsCfg.RootCAs, err = LoadCertPool(cfg.CAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		kp, err := NewKeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsCfg.GetClientCertificate = kp.GetClientCertificate
+	}
+
+	return tlsCfg, nil
+}
+
+// parseVersion accepts "1.2" as well as "TLS1.2" and "tls12"; empty means TLS 1.2.
+func parseVersion(v string) (uint16, error) {
+	if v == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	normalized := strings.TrimPrefix(strings.ToLower(v), "tls")
+	if !strings.Contains(normalized, ".") && len(normalized) == 2 {
+		normalized = normalized[:1] + "." + normalized[1:]
+	}
+
+	version, ok := versions[normalized]
+	if !ok {
+		return 0, fmt.Errorf("unknown tls version %q", v)
+	}
+
+	return version, nil
+}
+
+// parseCipherSuites resolves IANA names ("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256").
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
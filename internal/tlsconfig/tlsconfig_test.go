@@ -0,0 +1,399 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/QuizWars-Ecosystem/api-gateway/internal/config"
+)
+
+const serverName = "gateway.test"
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(t),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf for name and returns its PEM encoded cert and key.
+func (a *authority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(t),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func serial(t *testing.T) *big.Int {
+	t.Helper()
+
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// touch moves the modification time forward so rewrites within the
+// filesystem's timestamp resolution are still noticed.
+func touch(t *testing.T, paths ...string) {
+	t.Helper()
+
+	future := time.Now().Add(time.Minute)
+	for _, path := range paths {
+		if err := os.Chtimes(path, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writePair(t *testing.T, dir, prefix string, certPEM, keyPEM []byte) (certFile, keyFile string) {
+	t.Helper()
+	return writeFile(t, dir, prefix+".crt", certPEM), writeFile(t, dir, prefix+".key", keyPEM)
+}
+
+func serverTLS(t *testing.T, ca *authority, dir string) *config.TLS {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, serverName, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := writePair(t, dir, "server", certPEM, keyPEM)
+
+	return &config.TLS{Enabled: true, CertFile: certFile, KeyFile: keyFile}
+}
+
+// handshake runs both sides over a loopback connection and returns their errors.
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) (serverErr, clientErr error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer conn.Close()
+
+		srv := tls.Server(conn, serverCfg)
+		_ = srv.SetDeadline(time.Now().Add(5 * time.Second))
+		errs <- srv.Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	cl := tls.Client(conn, clientCfg)
+	_ = cl.SetDeadline(time.Now().Add(5 * time.Second))
+
+	clientErr = cl.Handshake()
+	if clientErr == nil {
+		// TLS 1.3 servers verify the client certificate after the client
+		// finished, so a rejection only shows up on the next read. The
+		// server closes right after its handshake, which reads as EOF.
+		if _, clientErr = cl.Read(make([]byte, 1)); errors.Is(clientErr, io.EOF) {
+			clientErr = nil
+		}
+	}
+
+	return <-errs, clientErr
+}
+
+func clientTLS(t *testing.T, caFile string) *tls.Config {
+	t.Helper()
+
+	cfg, err := NewClientConfig(&config.UpstreamTLS{Enabled: true, CAFile: caFile, ServerName: serverName})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+func TestServerHandshake(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+
+	serverCfg, err := NewServerConfig(serverTLS(t, ca, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverErr, clientErr := handshake(t, serverCfg, clientTLS(t, writeFile(t, dir, "ca.crt", ca.pem)))
+	if serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake failed: server %v, client %v", serverErr, clientErr)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+	caFile := writeFile(t, dir, "ca.crt", ca.pem)
+
+	cfg := serverTLS(t, ca, dir)
+	cfg.ClientAuth = "require_and_verify"
+	cfg.ClientCAFile = caFile
+
+	serverCfg, err := NewServerConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("without client cert", func(t *testing.T) {
+		serverErr, clientErr := handshake(t, serverCfg, clientTLS(t, caFile))
+		if serverErr == nil || clientErr == nil {
+			t.Fatalf("expected rejection: server %v, client %v", serverErr, clientErr)
+		}
+	})
+
+	t.Run("with untrusted client cert", func(t *testing.T) {
+		certPEM, keyPEM := newAuthority(t).issue(t, "client", x509.ExtKeyUsageClientAuth)
+		certFile, keyFile := writePair(t, t.TempDir(), "client", certPEM, keyPEM)
+
+		clientCfg, err := NewClientConfig(&config.UpstreamTLS{
+			Enabled: true, CAFile: caFile, ServerName: serverName, CertFile: certFile, KeyFile: keyFile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if serverErr, _ := handshake(t, serverCfg, clientCfg); serverErr == nil {
+			t.Fatal("expected the server to reject the client cert")
+		}
+	})
+
+	t.Run("with client cert", func(t *testing.T) {
+		certPEM, keyPEM := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+		certFile, keyFile := writePair(t, t.TempDir(), "client", certPEM, keyPEM)
+
+		clientCfg, err := NewClientConfig(&config.UpstreamTLS{
+			Enabled: true, CAFile: caFile, ServerName: serverName, CertFile: certFile, KeyFile: keyFile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		serverErr, clientErr := handshake(t, serverCfg, clientCfg)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("handshake failed: server %v, client %v", serverErr, clientErr)
+		}
+	})
+}
+
+func TestMutualTLSRequiresClientCA(t *testing.T) {
+	cfg := serverTLS(t, newAuthority(t), t.TempDir())
+	cfg.ClientAuth = "require_and_verify"
+
+	if _, err := NewServerConfig(cfg); err == nil {
+		t.Fatal("expected an error without a client ca file")
+	}
+}
+
+func TestUpstreamVerification(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+
+	serverCfg, err := NewServerConfig(serverTLS(t, ca, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		caPEM      []byte
+		serverName string
+		wantErr    bool
+	}{
+		{name: "trusted", caPEM: ca.pem, serverName: serverName},
+		{name: "wrong server name", caPEM: ca.pem, serverName: "other.test", wantErr: true},
+		{name: "unknown ca", caPEM: newAuthority(t).pem, serverName: serverName, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCfg, err := NewClientConfig(&config.UpstreamTLS{
+				Enabled:    true,
+				CAFile:     writeFile(t, t.TempDir(), "ca.crt", tt.caPEM),
+				ServerName: tt.serverName,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, clientErr := handshake(t, serverCfg, clientCfg)
+			if (clientErr != nil) != tt.wantErr {
+				t.Fatalf("client error %v, want error %v", clientErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyPairReload(t *testing.T) {
+	checkInterval = 0
+	t.Cleanup(func() { checkInterval = 10 * time.Second })
+
+	dir := t.TempDir()
+	ca := newAuthority(t)
+
+	certPEM, keyPEM := ca.issue(t, serverName, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := writePair(t, dir, "server", certPEM, keyPEM)
+
+	kp, err := NewKeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, _ := kp.GetCertificate(nil)
+
+	certPEM, keyPEM = ca.issue(t, serverName, x509.ExtKeyUsageServerAuth)
+	writePair(t, dir, "server", certPEM, keyPEM)
+	touch(t, certFile, keyFile)
+
+	after, _ := kp.GetCertificate(nil)
+	if after == before {
+		t.Fatal("expected the rewritten key pair to be served")
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if string(after.Certificate[0]) != string(block.Bytes) {
+		t.Fatal("served certificate does not match the rewritten file")
+	}
+
+	writeFile(t, dir, "server.key", []byte("garbage"))
+	touch(t, keyFile)
+
+	if current, _ := kp.GetCertificate(nil); current != after {
+		t.Fatal("expected a broken key pair to keep the previous certificate")
+	}
+}
+
+func TestClientCAReload(t *testing.T) {
+	checkInterval = 0
+	t.Cleanup(func() { checkInterval = 10 * time.Second })
+
+	dir := t.TempDir()
+	oldCA, newCA := newAuthority(t), newAuthority(t)
+	caFile := writeFile(t, dir, "client-ca.crt", oldCA.pem)
+
+	cfg := serverTLS(t, oldCA, dir)
+	cfg.ClientAuth = "require_and_verify"
+	cfg.ClientCAFile = caFile
+
+	serverCfg, err := NewServerConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, keyPEM := newCA.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	certFile, keyFile := writePair(t, dir, "client", certPEM, keyPEM)
+
+	clientCfg, err := NewClientConfig(&config.UpstreamTLS{
+		Enabled:    true,
+		CAFile:     writeFile(t, dir, "server-ca.crt", oldCA.pem),
+		ServerName: serverName,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if serverErr, _ := handshake(t, serverCfg, clientCfg); serverErr == nil {
+		t.Fatal("expected the client cert of the new ca to be rejected")
+	}
+
+	writeFile(t, dir, "client-ca.crt", newCA.pem)
+	touch(t, caFile)
+
+	if serverErr, clientErr := handshake(t, serverCfg, clientCfg); serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake after the ca rotation failed: server %v, client %v", serverErr, clientErr)
+	}
+}
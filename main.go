@@ -13,11 +13,10 @@ import (
 	gateway "github.com/QuizWars-Ecosystem/api-gateway/internal/config"
 	"github.com/QuizWars-Ecosystem/api-gateway/internal/server"
 	"github.com/QuizWars-Ecosystem/go-common/pkg/abstractions"
-	"github.com/QuizWars-Ecosystem/go-common/pkg/config"
 )
 
 func main() {
-	cfg, err := config.Load[gateway.Config]()
+	cfg, err := gateway.Load()
 	if err != nil {
 		slog.Error("Error loading config: ", "error", err)
 		return
@@ -34,7 +33,12 @@ func main() {
 		return
 	}
 
+	// Start returns once the listeners close, the drain still has to finish.
+	shutdownDone := make(chan struct{})
+
 	go func() {
+		defer close(shutdownDone)
+
 		signalCh := make(chan os.Signal, 1)
 		signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 
@@ -52,5 +56,8 @@ func main() {
 
 	if err = srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slog.Error("Server failed to start", "error", err)
+		return
 	}
+
+	<-shutdownDone
 }